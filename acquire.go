@@ -0,0 +1,51 @@
+package quartz
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrAcquireTimeout is returned by AcquireWithin and TryLockWithin if the semaphore or lock is not
+// acquired before the timeout.
+var ErrAcquireTimeout = errors.New("timed out acquiring")
+
+// Semaphore is a weighted semaphore, such as the *Weighted of golang.org/x/sync/semaphore.
+type Semaphore interface {
+	Acquire(ctx context.Context, n int64) error
+}
+
+// AcquireWithin acquires a weight of 1 from sem, giving up once d has elapsed on clk or ctx
+// expires. It returns nil if the weight was acquired, ErrAcquireTimeout, or the context error.
+//
+// The timeout is a WithTimeout on clk, so with a Mock the timeout path can be tested by advancing
+// the clock by d. The tags are passed to the Clock call.
+func AcquireWithin(ctx context.Context, clk Clock, sem Semaphore, d time.Duration, tags ...string) error {
+	tctx, cancel := clk.WithTimeout(ctx, d, tags...)
+	defer cancel()
+	err := sem.Acquire(tctx, 1)
+	if err != nil && ctx.Err() == nil && tctx.Err() != nil {
+		return ErrAcquireTimeout
+	}
+	return err
+}
+
+// TryLocker is a lock that can be acquired without blocking, such as *sync.Mutex.
+type TryLocker interface {
+	TryLock() bool
+}
+
+// TryLockWithin tries to acquire l every interval on clk, using WaitFor, giving up once d has
+// elapsed or ctx expires. It returns nil if the lock was acquired, ErrAcquireTimeout, or the
+// context error.
+func TryLockWithin(
+	ctx context.Context, clk Clock, l TryLocker, interval, d time.Duration, tags ...string,
+) error {
+	err := WaitFor(ctx, clk, interval, d, func() (bool, error) {
+		return l.TryLock(), nil
+	}, tags...)
+	if errors.Is(err, ErrWaitTimeout) {
+		return ErrAcquireTimeout
+	}
+	return err
+}
@@ -0,0 +1,27 @@
+package quartz
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// AssertNoClockCalls runs f, and fails the test if any call is made into m, or a Timer or Ticker it
+// created, while f runs, from any goroutine. This enforces that hot paths that are forbidden from
+// touching the clock stay that way. It returns whether no calls were made.
+func AssertNoClockCalls(tb testing.TB, m *Mock, f func()) bool {
+	tb.Helper()
+	var calls []string
+	m.mu.Lock()
+	m.callRecorders = append(m.callRecorders, &calls)
+	m.mu.Unlock()
+	f()
+	m.mu.Lock()
+	m.callRecorders = slices.DeleteFunc(m.callRecorders, func(r *[]string) bool { return r == &calls })
+	m.mu.Unlock()
+	if len(calls) > 0 {
+		tb.Errorf("expected no clock calls, but got %d: %s", len(calls), strings.Join(calls, ", "))
+		return false
+	}
+	return true
+}
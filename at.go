@@ -0,0 +1,51 @@
+package quartz
+
+import "time"
+
+// At schedules f to be called when the clock reaches t, alongside any timers and tickers scheduled
+// for the same time. This lets tests inject external stimuli, like a message arriving, at a
+// precise point in the timeline of the code under test. Like the functions passed to AfterFunc, f
+// is called on its own goroutine, and the AdvanceWaiter waits for it to return.
+//
+// The event is visible to Peek, and its tags are reported by PeekN and in logs, but it is not
+// trappable, since it is scheduled by the test rather than the code under test. It fails the test
+// if t is not in the future.
+func (m *Mock) At(t time.Time, f func(), tags ...string) {
+	m.tb.Helper()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !t.After(m.cur) {
+		m.tb.Errorf("cannot schedule event At(%s, %v), which is not after the current time %s",
+			t.Format(time.RFC3339Nano), tags, m.cur.Format(time.RFC3339Nano))
+		return
+	}
+	if !m.testOver {
+		m.logfTaggedLocked(tags, "At(%s, %v)", t.Format(time.RFC3339Nano), tags)
+	}
+	m.addEventLocked(&userEvent{mock: m, kind: "At", tags: tags, d: t.Sub(m.cur), nxt: t, f: f})
+}
+
+// userEvent is an event scheduled by the test, e.g. with At.
+type userEvent struct {
+	mock *Mock
+	kind string
+	tags []string
+	d    time.Duration
+	nxt  time.Time
+	f    func()
+}
+
+func (e *userEvent) next() time.Time {
+	return e.nxt
+}
+
+func (e *userEvent) fire(time.Time) {
+	e.mock.mu.Lock()
+	e.mock.removeEventLocked(e)
+	e.mock.mu.Unlock()
+	e.f()
+}
+
+func (e *userEvent) info() EventInfo {
+	return EventInfo{Kind: e.kind, Tags: e.tags, Deadline: e.nxt, Duration: e.d}
+}
@@ -0,0 +1,124 @@
+package quartz
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// AuditClock is a Clock for integration tests that passes through to another Clock, usually the
+// real one, while recording when the functions passed to AfterFunc, TickerFunc and TimerFunc were
+// called, compared with when they would have been called under a Mock. Under a Mock, virtual time
+// doesn't pass while code runs, so divergences point to code paths that depend on real time
+// passing, like real sleeps, or callbacks that are slow enough to make tickers skip.
+//
+// Timers and tickers created with NewTimer and NewTicker are passed through without being audited.
+type AuditClock struct {
+	Clock
+	tb        testing.TB
+	tolerance time.Duration
+
+	mu      sync.Mutex
+	records []AuditRecord
+}
+
+// AuditRecord records a call of a function scheduled on an AuditClock.
+type AuditRecord struct {
+	// Kind is the name of the Clock method that scheduled the call, e.g. "AfterFunc".
+	Kind string
+	Tags []string
+	// Expected is the time the call would have happened under a Mock, and Actual the time it
+	// happened.
+	Expected time.Time
+	Actual   time.Time
+}
+
+// Divergence returns how late, or if negative how early, the call was.
+func (r AuditRecord) Divergence() time.Duration {
+	return r.Actual.Sub(r.Expected)
+}
+
+func (r AuditRecord) String() string {
+	return fmt.Sprintf("%s(%v) expected at %s, called %s late",
+		r.Kind, r.Tags, r.Expected.Format(time.RFC3339Nano), r.Divergence())
+}
+
+// NewAuditClock returns an AuditClock that passes through to clk, and at the end of the test, logs
+// the calls that diverged from their expected time by more than tolerance.
+func NewAuditClock(tb testing.TB, clk Clock, tolerance time.Duration) *AuditClock {
+	a := &AuditClock{Clock: clk, tb: tb, tolerance: tolerance}
+	tb.Cleanup(func() {
+		divergences := a.Divergences()
+		if len(divergences) == 0 {
+			return
+		}
+		tb.Logf("Audit Clock - %d calls diverged from their schedule by more than %s:",
+			len(divergences), tolerance)
+		for _, r := range divergences {
+			tb.Logf("Audit Clock - %s", r)
+		}
+	})
+	return a
+}
+
+// Records returns all the calls recorded by the AuditClock.
+func (a *AuditClock) Records() []AuditRecord {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]AuditRecord(nil), a.records...)
+}
+
+// Divergences returns the calls recorded by the AuditClock that diverged from their expected
+// time by more than the tolerance.
+func (a *AuditClock) Divergences() []AuditRecord {
+	var out []AuditRecord
+	for _, r := range a.Records() {
+		if d := r.Divergence(); d > a.tolerance || d < -a.tolerance {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+func (a *AuditClock) record(fn clockFunction, tags []string, expected time.Time) {
+	actual := a.Clock.Now()
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.records = append(a.records, AuditRecord{
+		Kind:     fn.String(),
+		Tags:     tags,
+		Expected: expected,
+		Actual:   actual,
+	})
+}
+
+func (a *AuditClock) AfterFunc(d time.Duration, f func(), tags ...string) *Timer {
+	expected := a.Clock.Now().Add(max(d, 0))
+	return a.Clock.AfterFunc(d, func() {
+		a.record(clockFunctionAfterFunc, tags, expected)
+		f()
+	}, tags...)
+}
+
+func (a *AuditClock) TickerFunc(ctx context.Context, d time.Duration, f func() error, tags ...string) StopWaiter {
+	start := a.Clock.Now()
+	n := 0
+	return a.Clock.TickerFunc(ctx, d, func() error {
+		// calls of f never overlap, so n needs no lock.
+		n++
+		a.record(clockFunctionTickerFunc, tags, start.Add(time.Duration(n)*d))
+		return f()
+	}, tags...)
+}
+
+func (a *AuditClock) TimerFunc(ctx context.Context, d time.Duration, f func() error, tags ...string) Waiter {
+	expected := a.Clock.Now().Add(max(d, 0))
+	return a.Clock.TimerFunc(ctx, d, func() error {
+		a.record(clockFunctionTimerFunc, tags, expected)
+		return f()
+	}, tags...)
+}
+
+var _ Clock = &AuditClock{}
@@ -0,0 +1,47 @@
+package quartz_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/coder/quartz"
+)
+
+func TestAuditClock(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	clk := quartz.NewAuditClock(t, quartz.NewReal(), 15*time.Millisecond)
+	calls := 0
+	err := clk.TickerFunc(ctx, 10*time.Millisecond, func() error {
+		calls++
+		if calls == 1 {
+			// a real sleep, which makes the ticker skip ticks
+			time.Sleep(35 * time.Millisecond)
+		}
+		if calls == 2 {
+			return quartz.ErrStopped
+		}
+		return nil
+	}, "audited").Wait()
+	if !errors.Is(err, quartz.ErrStopped) {
+		t.Fatalf("unexpected error %v", err)
+	}
+	records := clk.Records()
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, got %v", records)
+	}
+	if records[0].Kind != "TickerFunc" || records[0].Tags[0] != "audited" {
+		t.Fatalf("unexpected record %s", records[0])
+	}
+	// the second tick was expected 10ms after the first, but happened after the sleep.
+	if d := records[1].Divergence(); d < 15*time.Millisecond {
+		t.Fatalf("expected second tick to diverge, got %s", d)
+	}
+	if len(clk.Divergences()) == 0 {
+		t.Fatal("expected divergences")
+	}
+}
@@ -0,0 +1,95 @@
+package quartz
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// AutoAdvance starts advancing the Mock automatically, for integration-style tests that don't
+// choreograph each Advance. Whenever the code under test has been idle for settle, in real time,
+// that is, it hasn't called the Mock or scheduled any events, and no advance is in progress, the
+// Mock advances to the next event, as if by AdvanceNext, and waits for it to complete. Each step
+// can be trapped by AdvanceNext traps, and is logged.
+//
+// Detecting idleness this way is a heuristic: the Mock cannot tell whether the code under test is
+// blocked waiting for a timer, or just slow, so on a loaded machine a step can happen before the
+// code under test reaches its next wait, and the interleaving differs from run to run. Events are
+// always fired in schedule order, but tests that need exact interleavings should advance
+// explicitly, or use WithSynctest, which waits until the code under test is blocked.
+//
+// Auto-advancing stops when ctx expires, at the end of the test, or when the returned function is
+// called, which waits for any step in progress to finish.
+func (m *Mock) AutoAdvance(ctx context.Context, settle time.Duration) (stop func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		m.autoAdvance(ctx, settle)
+	}()
+	var once sync.Once
+	stop = func() {
+		once.Do(func() {
+			cancel()
+			wg.Wait()
+		})
+	}
+	m.tb.Cleanup(stop)
+	return stop
+}
+
+func (m *Mock) autoAdvance(ctx context.Context, settle time.Duration) {
+	m.mu.Lock()
+	last := m.activity
+	m.mu.Unlock()
+	tmr := time.NewTimer(settle)
+	defer tmr.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tmr.C:
+		}
+		tmr.Reset(settle)
+		m.mu.Lock()
+		idle := m.activity == last && len(m.advancing) == 0 && !m.nextTime.IsZero() && !m.testOver
+		last = m.activity
+		m.mu.Unlock()
+		if !idle {
+			continue
+		}
+		w, ok := m.autoStep()
+		if !ok {
+			continue
+		}
+		// the step completes even if stopped meanwhile, so that stop waits for it.
+		_ = w.Wait(context.WithoutCancel(ctx))
+		if ctx.Err() != nil {
+			return
+		}
+		m.mu.Lock()
+		last = m.activity
+		m.mu.Unlock()
+	}
+}
+
+// autoStep advances to the next event, like AdvanceNext, unless the events were stopped since the
+// Mock was found idle. It returns whether it advanced.
+func (m *Mock) autoStep() (AdvanceWaiter, bool) {
+	defer m.settle()
+	m.runBeforeAdvance()
+	w := newAdvanceWaiter(m)
+	m.mu.Lock()
+	c := newCall(clockFunctionAdvanceNext, nil)
+	defer close(c.complete)
+	m.matchHarnessCallLocked(c)
+	if m.nextTime.IsZero() || m.testOver {
+		m.mu.Unlock()
+		close(w.ch)
+		return w, false
+	}
+	m.logfLocked("AutoAdvance(%s)", m.nextTime.Sub(m.cur))
+	m.advanceNextLocked(w)
+	return w, true
+}
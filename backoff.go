@@ -0,0 +1,121 @@
+package quartz
+
+import (
+	"math"
+	"slices"
+	"time"
+)
+
+// Backoff describes an expected backoff progression, for AssertBackoff. The nth wait (counting from
+// zero) is expected to be Initial * Factor^n, capped at Max, give or take Jitter.
+type Backoff struct {
+	// Initial is the expected first wait.
+	Initial time.Duration
+	// Factor is the growth factor between waits, e.g. 2 for exponential backoff. A Factor of zero
+	// is treated as 1, i.e. a constant backoff.
+	Factor float64
+	// Max, if positive, caps the expected waits.
+	Max time.Duration
+	// Jitter is the tolerance on each wait, as a fraction of the expected wait, e.g. 0.2 allows
+	// waits within 20% of the expected wait.
+	Jitter float64
+}
+
+// Expected returns the expected nth wait, counting from zero, without jitter.
+func (b Backoff) Expected(n int) time.Duration {
+	f := b.Factor
+	if f == 0 {
+		f = 1
+	}
+	d := float64(b.Initial) * math.Pow(f, float64(n))
+	if b.Max > 0 && d > float64(b.Max) {
+		return b.Max
+	}
+	if d >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return time.Duration(d)
+}
+
+// Scheduled returns the timer and tick events that have been scheduled on the Mock, in the order
+// they were scheduled, whose tags include all the given tags. Each Reset of a Timer, and each
+// interval of an AdaptiveTickerFunc, is a separately scheduled event. The Duration of each event is
+// the time from when it was scheduled until its deadline, i.e. the duration requested by the code
+// under test, perturbed if WithPerturbation is used.
+//
+// Only the most recent 10000 events are kept, to bound the memory used by long tests; see
+// WithScheduledLimit.
+func (m *Mock) Scheduled(tags ...string) []EventInfo {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var infos []EventInfo
+	for _, e := range m.scheduled {
+		if containsAll(e.Tags, tags) {
+			infos = append(infos, e)
+		}
+	}
+	return infos
+}
+
+// AssertBackoff asserts that the waits scheduled on the Mock with the given tags follow the
+// Backoff progression, and fails the test if they do not, or if there are none. It returns whether
+// the assertion succeeded. It can be called after any number of waits have been scheduled, and
+// checks all of them.
+func (m *Mock) AssertBackoff(b Backoff, tags ...string) bool {
+	m.tb.Helper()
+	m.mu.Lock()
+	dropped := m.scheduledDropped
+	m.mu.Unlock()
+	if dropped > 0 {
+		m.tb.Errorf("cannot assert backoff: %d scheduled events were dropped beyond the limit; "+
+			"see WithScheduledLimit", dropped)
+		return false
+	}
+	waits := m.Scheduled(tags...)
+	if len(waits) == 0 {
+		m.tb.Errorf("no waits scheduled with tags %v", tags)
+		return false
+	}
+	ok := true
+	for i, w := range waits {
+		want := b.Expected(i)
+		tol := time.Duration(float64(want) * b.Jitter)
+		if w.Duration < want-tol || w.Duration > want+tol {
+			m.tb.Errorf("wait %d with tags %v: expected %s ± %s, got %s", i, tags, want, tol, w.Duration)
+			ok = false
+		}
+	}
+	return ok
+}
+
+// defaultScheduledLimit is the number of events Scheduled keeps by default.
+const defaultScheduledLimit = 10000
+
+// WithScheduledLimit sets the number of most recent scheduled events kept for Scheduled and
+// AssertBackoff, which is 10000 by default. A negative limit keeps them all.
+func (m *Mock) WithScheduledLimit(n int) *Mock {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if n == 0 {
+		panic("WithScheduledLimit called with zero limit")
+	}
+	m.scheduledLimit = n
+	return m
+}
+
+// recordScheduledLocked records the event as scheduled, for Scheduled, dropping the oldest event
+// if there are more than the limit.
+func (m *Mock) recordScheduledLocked(e event) {
+	info := e.info()
+	info.Tags = slices.Clone(info.Tags)
+	info.Duration = info.Deadline.Sub(m.cur)
+	limit := m.scheduledLimit
+	if limit == 0 {
+		limit = defaultScheduledLimit
+	}
+	if limit > 0 && len(m.scheduled) >= limit {
+		m.scheduled = slices.Delete(m.scheduled, 0, len(m.scheduled)-limit+1)
+		m.scheduledDropped++
+	}
+	m.scheduled = append(m.scheduled, info)
+}
@@ -0,0 +1,65 @@
+package quartz_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/coder/quartz"
+)
+
+func TestAssertBackoff(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	jittered := []time.Duration{
+		1100 * time.Millisecond, 1800 * time.Millisecond, 4 * time.Second, 7 * time.Second, 10 * time.Second,
+	}
+	mClock := quartz.NewMock(t)
+	tmr := mClock.NewTimer(jittered[0], "retry")
+	mClock.NewTimer(time.Hour, "other")
+	for _, d := range jittered[1:] {
+		_, w := mClock.AdvanceNext()
+		w.MustWait(ctx)
+		tmr.Reset(d, "retry")
+	}
+	b := quartz.Backoff{Initial: time.Second, Factor: 2, Max: 10 * time.Second, Jitter: 0.2}
+	if !mClock.AssertBackoff(b, "retry") {
+		t.Fatal("expected backoff to match")
+	}
+	if got := mClock.Scheduled("retry"); len(got) != len(jittered) {
+		t.Fatalf("expected %d scheduled waits, got %d", len(jittered), len(got))
+	}
+
+	tRunFail(t, func(tb testing.TB) {
+		mClock := quartz.NewMock(tb)
+		mClock.NewTimer(time.Second, "retry")
+		mClock.Advance(time.Second).MustWait(ctx)
+		mClock.NewTimer(time.Second, "retry")
+		mClock.AssertBackoff(b, "retry")
+	})
+	tRunFail(t, func(tb testing.TB) {
+		mClock := quartz.NewMock(tb)
+		mClock.AssertBackoff(b, "retry")
+	})
+}
+
+func TestWithScheduledLimit(t *testing.T) {
+	t.Parallel()
+
+	mClock := quartz.NewMock(t).WithScheduledLimit(2)
+	tmr := mClock.NewTimer(time.Second, "retry")
+	tmr.Reset(2*time.Second, "retry")
+	tmr.Reset(3*time.Second, "retry")
+	got := mClock.Scheduled("retry")
+	if len(got) != 2 || got[0].Duration != 2*time.Second || got[1].Duration != 3*time.Second {
+		t.Fatalf("expected the 2 most recent waits, got %v", got)
+	}
+	tRunFail(t, func(tb testing.TB) {
+		mClock := quartz.NewMock(tb).WithScheduledLimit(1)
+		mClock.NewTimer(time.Second, "retry")
+		mClock.NewTimer(2*time.Second, "retry")
+		mClock.AssertBackoff(quartz.Backoff{Initial: time.Second, Factor: 2}, "retry")
+	})
+}
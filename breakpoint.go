@@ -0,0 +1,78 @@
+package quartz
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Breakpoint pauses advances of a Mock at a point in virtual time, see Mock.Breakpoint.
+type Breakpoint struct {
+	tb     testing.TB
+	at     time.Time
+	hit    chan struct{}
+	resume chan struct{}
+	once   sync.Once
+}
+
+// Breakpoint sets a breakpoint in virtual time: when an advance reaches at, it pauses, as if an
+// event scheduled at at were still running, until Resume is called. This returns control to the
+// test in the middle of long simulations, e.g. an AdvanceTo spanning several hours, so that it can
+// inspect or change state at that instant before letting the advance continue. Other events
+// scheduled at the same instant fire concurrently with the pause.
+//
+// Like other events, the breakpoint is visible to Peek, and Advance cannot advance past it. It fails
+// the test if at is not in the future. Breakpoints still paused at the end of the test are resumed.
+func (m *Mock) Breakpoint(at time.Time, tags ...string) *Breakpoint {
+	m.tb.Helper()
+	b := &Breakpoint{tb: m.tb, at: at, hit: make(chan struct{}), resume: make(chan struct{})}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !at.After(m.cur) {
+		m.tb.Errorf("cannot set Breakpoint(%s, %v), which is not after the current time %s",
+			at.Format(time.RFC3339Nano), tags, m.cur.Format(time.RFC3339Nano))
+		b.Resume()
+		return b
+	}
+	if !m.testOver {
+		m.logfTaggedLocked(tags, "Breakpoint(%s, %v)", at.Format(time.RFC3339Nano), tags)
+	}
+	m.addEventLocked(&userEvent{
+		mock: m,
+		kind: "Breakpoint",
+		tags: tags,
+		d:    at.Sub(m.cur),
+		nxt:  at,
+		f: func() {
+			close(b.hit)
+			<-b.resume
+		},
+	})
+	m.tb.Cleanup(b.Resume)
+	return b
+}
+
+// Wait waits until an advance reaches the breakpoint and pauses, or the context expires.
+func (b *Breakpoint) Wait(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-b.hit:
+		return nil
+	}
+}
+
+// MustWait calls Wait, and fails the test immediately if the context expires.
+func (b *Breakpoint) MustWait(ctx context.Context) {
+	if err := b.Wait(ctx); err != nil {
+		b.tb.Helper()
+		b.tb.Fatalf("context expired while waiting for breakpoint at %s: %s", b.at.Format(time.RFC3339Nano), err)
+	}
+}
+
+// Resume lets the advance paused at the breakpoint continue. If the breakpoint has not been
+// reached yet, advances will no longer pause at it. It is safe to call more than once.
+func (b *Breakpoint) Resume() {
+	b.once.Do(func() { close(b.resume) })
+}
@@ -0,0 +1,58 @@
+package quartz
+
+import "time"
+
+// CallRecordVersion is the version of the CallRecord format. It is incremented on any change that
+// is not backwards compatible, i.e. anything but adding fields.
+const CallRecordVersion = 1
+
+// CallRecord is a stable, JSON-serializable description of a trapped Call, so that external
+// harnesses and assertion libraries can consume call data without depending on quartz's types.
+// Durations are formatted as strings that time.ParseDuration accepts, and times as RFC 3339.
+type CallRecord struct {
+	// Version is the CallRecordVersion of the format.
+	Version int `json:"version"`
+	// Method is the name of the Clock, Timer or Ticker method called, e.g. "NewTimer".
+	Method string `json:"method"`
+	// Duration is the duration argument of the call, or "0s" if it doesn't take one.
+	Duration string `json:"duration"`
+	// Time is the time argument of the call, for calls that take one.
+	Time *time.Time `json:"time,omitempty"`
+	// Tags are the tags the call was made with.
+	Tags []string `json:"tags"`
+	// VirtualTime is the time on the Mock when the call was made, and RealTime the wall clock time.
+	VirtualTime time.Time `json:"virtual_time"`
+	RealTime    time.Time `json:"real_time"`
+	// Released is whether the call has been released, and Canceled whether it was released with
+	// Cancel. ReleasedWith is the substitute time the call was released with, see ReleaseWith.
+	Released     bool       `json:"released"`
+	Canceled     bool       `json:"canceled,omitempty"`
+	ReleasedWith *time.Time `json:"released_with,omitempty"`
+}
+
+// Record returns the CallRecord describing the call. Like the other fields of the Call, it should
+// not be called concurrently with releasing the call.
+func (c *Call) Record() CallRecord {
+	r := CallRecord{
+		Version:     CallRecordVersion,
+		Method:      c.apiCall.fn.String(),
+		Duration:    c.Duration.String(),
+		Tags:        c.Tags,
+		VirtualTime: c.VirtualTime,
+		RealTime:    c.RealTime,
+		Released:    c.released.Load(),
+		Canceled:    c.apiCall.canceled,
+	}
+	if r.Tags == nil {
+		r.Tags = []string{}
+	}
+	if !c.Time.IsZero() {
+		t := c.Time
+		r.Time = &t
+	}
+	if c.apiCall.now != nil {
+		t := *c.apiCall.now
+		r.ReleasedWith = &t
+	}
+	return r
+}
@@ -0,0 +1,95 @@
+package quartz
+
+import (
+	"math"
+	"math/rand/v2"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// SeedEnv is the environment variable consulted for a seed when the randomized testing modes of a
+// Mock are enabled without an explicit call to WithSeed. Set it to the seed logged by a failing
+// test to reproduce the same schedule decisions locally.
+const SeedEnv = "QUARTZ_SEED"
+
+// WithSeed sets the seed used by the randomized testing modes of the Mock, such as
+// WithPerturbation. Using the same seed reproduces the same randomized decisions, so long as the
+// code under test makes the same sequence of calls. If no seed is set, the seed is read from the
+// QUARTZ_SEED environment variable, or chosen at random if that is unset.
+func (m *Mock) WithSeed(seed int64) *Mock {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.seed = seed
+	m.seedSet = true
+	m.rand = nil
+	return m
+}
+
+// WithPerturbation enables a mode where every duration scheduled on the Mock (by NewTimer,
+// AfterFunc, NewTicker, TickerFunc and the Reset methods) is perturbed by a random amount of up to
+// ±fraction of its value. This is useful for flushing out tests that only pass because of
+// exact-duration coincidences, like two independent 5 second timers assumed to fire together.
+//
+// Traps still see the duration passed by the code under test; only the scheduled event is
+// perturbed. The seed is logged so that failures can be reproduced with WithSeed.
+func (m *Mock) WithPerturbation(fraction float64) *Mock {
+	if fraction < 0 || fraction >= 1 {
+		panic("WithPerturbation called with fraction outside [0, 1)")
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.perturbation = fraction
+	m.randLocked()
+	return m
+}
+
+// randLocked returns the random source for the Mock, creating it and logging the seed on first
+// use.
+func (m *Mock) randLocked() *rand.Rand {
+	if m.rand != nil {
+		return m.rand
+	}
+	source := "WithSeed"
+	if !m.seedSet {
+		m.seed, source = seedFromEnv(m.tb)
+		m.seedSet = true
+	}
+	m.rand = rand.New(rand.NewPCG(uint64(m.seed), 0))
+	if !m.testOver {
+		m.logfLocked("using seed %d (from %s)", m.seed, source)
+	}
+	return m.rand
+}
+
+// seedFromEnv returns the seed from the QUARTZ_SEED environment variable if set, otherwise a random
+// seed, along with a description of where it came from.
+func seedFromEnv(tb testing.TB) (int64, string) {
+	if v := os.Getenv(SeedEnv); v != "" {
+		seed, err := strconv.ParseInt(v, 10, 64)
+		if err == nil {
+			return seed, SeedEnv
+		}
+		tb.Errorf("invalid %s %q: %s", SeedEnv, v, err)
+	}
+	return rand.Int64(), "random"
+}
+
+// scheduleDurationLocked returns the duration an event should actually be scheduled after, when
+// the code under test makes the call c, which requests c.Duration.
+func (m *Mock) scheduleDurationLocked(c *apiCall) time.Duration {
+	m.checkDurationLocked(c)
+	d := c.Duration
+	if m.perturbation == 0 || d <= 0 {
+		return d
+	}
+	delta := time.Duration((m.randLocked().Float64()*2 - 1) * m.perturbation * float64(d))
+	if delta > 0 && d > math.MaxInt64-delta {
+		return math.MaxInt64
+	}
+	if d+delta <= 0 {
+		return 1
+	}
+	return d + delta
+}
@@ -0,0 +1,59 @@
+package quartz
+
+import (
+	"slices"
+	"strings"
+)
+
+// TimerStats counts the timers of a Mock and what happened to them, to measure the timer churn of
+// the code under test, e.g. to assert that a refactor reduced the timers created in a hot loop.
+type TimerStats struct {
+	// Created is the number of timers created with NewTimer, NewTimerInto, After and AfterFunc.
+	Created int
+	// Stopped is the number of Stop calls that stopped a timer before it fired.
+	Stopped int
+	// Fired is the number of times timers fired.
+	Fired int
+	// Reset is the number of Reset calls.
+	Reset int
+}
+
+// timerCounts are the counts of the timers created with a particular set of tags, see
+// TimerStats. Counting per tag set, rather than keeping the timers, keeps memory bounded for code
+// that churns through many timers.
+type timerCounts struct {
+	tags  []string
+	stats TimerStats
+}
+
+// TimerStats returns the counts for the timers whose tags include all the given tags.
+func (m *Mock) TimerStats(tags ...string) TimerStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var s TimerStats
+	for _, c := range m.timerCounts {
+		if !containsAll(c.tags, tags) {
+			continue
+		}
+		s.Created += c.stats.Created
+		s.Stopped += c.stats.Stopped
+		s.Fired += c.stats.Fired
+		s.Reset += c.stats.Reset
+	}
+	return s
+}
+
+// trackTimerLocked counts a timer created by the code under test, for TimerStats.
+func (m *Mock) trackTimerLocked(t *Timer) {
+	key := strings.Join(t.tags, "\x00")
+	c, ok := m.timerCounts[key]
+	if !ok {
+		if m.timerCounts == nil {
+			m.timerCounts = make(map[string]*timerCounts)
+		}
+		c = &timerCounts{tags: slices.Clone(t.tags)}
+		m.timerCounts[key] = c
+	}
+	c.stats.Created++
+	t.counts = &c.stats
+}
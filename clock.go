@@ -6,6 +6,7 @@ package quartz
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
@@ -17,18 +18,53 @@ type Clock interface {
 	// release associated resources.
 	NewTicker(d time.Duration, tags ...string) *Ticker
 	// TickerFunc is a convenience function that calls f on the interval d until either the given
-	// context expires or f returns an error.  Callers may call Wait() on the returned Waiter to
-	// wait until this happens and obtain the error. The duration d must be greater than zero; if
+	// context expires, f returns an error, or Stop() is called on the returned StopWaiter. Callers
+	// may call Wait() on the returned StopWaiter to wait until this happens and obtain the error,
+	// which is ErrStopped if the ticker was stopped. The duration d must be greater than zero; if
 	// not, TickerFunc will panic.
-	TickerFunc(ctx context.Context, d time.Duration, f func() error, tags ...string) Waiter
+	TickerFunc(ctx context.Context, d time.Duration, f func() error, tags ...string) StopWaiter
+	// AdaptiveTickerFunc is a variant of TickerFunc for adaptive polling loops, where the interval
+	// before each call of f is computed by calling interval with the number of calls of f so far
+	// and the error returned by the last one (0 and nil for the first interval). Errors returned by
+	// f don't stop the loop; instead, it stops when interval returns zero or a negative duration,
+	// and Wait returns the last error returned by f. Like TickerFunc, it also stops when the
+	// context expires, or Stop() is called. The next interval starts once f returns.
+	AdaptiveTickerFunc(ctx context.Context, interval func(n int, last error) time.Duration, f func() error,
+		tags ...string) StopWaiter
+	// TimerFunc is the one-shot analog of TickerFunc: it calls f once after the duration d, unless
+	// the given context expires first. Callers may call Wait() on the returned Waiter to wait until
+	// this happens and obtain the error returned by f, or the context error. Wait never returns
+	// while f is running.
+	TimerFunc(ctx context.Context, d time.Duration, f func() error, tags ...string) Waiter
 	// NewTimer creates a new Timer that will send the current time on its channel after at least
 	// duration d.
 	NewTimer(d time.Duration, tags ...string) *Timer
+	// NewTimerInto is like NewTimer, but sends the current time on ch instead of the returned
+	// Timer's channel, so that several timers can deliver to a single channel. The returned
+	// Timer's C field is nil. As with NewTimer, the send happens only once ch is read, and is
+	// abandoned if the Timer is stopped or reset first.
+	NewTimerInto(ch chan<- time.Time, d time.Duration, tags ...string) *Timer
+	// After waits for the duration to elapse and then sends the current time on the returned
+	// channel. It is equivalent to NewTimer(d).C.
+	After(d time.Duration, tags ...string) <-chan time.Time
+	// Sleep pauses the current goroutine for at least the duration d. A negative or zero duration
+	// causes Sleep to return immediately.
+	Sleep(d time.Duration, tags ...string)
+	// SleepContext is like Sleep, but returns ctx.Err() early if the context expires first. It
+	// returns nil if the full duration elapsed.
+	SleepContext(ctx context.Context, d time.Duration, tags ...string) error
 	// AfterFunc waits for the duration to elapse and then calls f in its own goroutine. It returns
 	// a Timer that can be used to cancel the call using its Stop method. The returned Timer's C
 	// field is not used and will be nil.
 	AfterFunc(d time.Duration, f func(), tags ...string) *Timer
 
+	// WithTimeout returns a copy of ctx that is canceled once d has elapsed on the Clock, or when
+	// the returned cancel function is called, like context.WithTimeout.
+	WithTimeout(ctx context.Context, d time.Duration, tags ...string) (context.Context, context.CancelFunc)
+	// WithDeadline returns a copy of ctx that is canceled once the Clock reaches the deadline t, or
+	// when the returned cancel function is called, like context.WithDeadline.
+	WithDeadline(ctx context.Context, t time.Time, tags ...string) (context.Context, context.CancelFunc)
+
 	// Now returns the current local time.
 	Now(tags ...string) time.Time
 	// Since returns the time elapsed since t. It is shorthand for Clock.Now().Sub(t).
@@ -41,3 +77,15 @@ type Clock interface {
 type Waiter interface {
 	Wait(tags ...string) error
 }
+
+// StopWaiter is a Waiter that can also be stopped, independently of the context it was started
+// with.
+type StopWaiter interface {
+	Waiter
+	// Stop halts the loop. Wait returns ErrStopped, unless the loop had already exited for another
+	// reason.
+	Stop(tags ...string)
+}
+
+// ErrStopped is returned by Wait after a StopWaiter is stopped.
+var ErrStopped = errors.New("stopped")
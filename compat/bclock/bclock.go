@@ -0,0 +1,192 @@
+// Package bclock adapts a quartz.Clock to the Clock interface of github.com/benbjohnson/clock, so
+// that dependencies taking that interface share the virtual time of a quartz.Mock with the rest of
+// the code under test.
+package bclock
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+
+	"github.com/coder/quartz"
+)
+
+// Tag is the tag of every call made through the adapter, so that tests can trap the calls of
+// libraries using benbjohnson/clock, e.g. mClock.Trap().Now(bclock.Tag).
+const Tag = "bclock"
+
+// New returns a clock.Clock backed by clk. Tag, followed by the given tags, is passed to each call
+// on clk, so the calls can be trapped like native calls.
+//
+// Now, Since, Until, After, Sleep, WithTimeout and WithDeadline are calls on clk. However,
+// clock.Timer and clock.Ticker can only be created by the clock package, so Timer, AfterFunc,
+// Ticker and Tick create them on a clock.Mock that follows clk, or, if clk is not a *quartz.Mock, on
+// the real clock. The adapter records the deadline of each Timer and the period of each Ticker it
+// creates, and keeps a single event, an AfterFunc, scheduled on the quartz.Mock at the earliest
+// of them, which moves the clock.Mock forward to the Mock's time, so that they fire when the Mock is
+// advanced. The functions passed to AfterFunc are called asynchronously by the clock.Mock, so the
+// quartz.AdvanceWaiter does not wait for them.
+//
+// clock.Timer and clock.Ticker are concrete types, so the adapter cannot observe calls to their Stop
+// and Reset methods. A stopped Ticker keeps scheduling the event at each of its ticks, although
+// nothing fires, and a Timer or Ticker reset to a later time fires only once the Mock is next
+// advanced after its new deadline, at the start of that advance, with its deadline as the time.
+//
+// Since the event carries the tags, creating a Timer or Ticker, or calling AfterFunc, can be
+// trapped as an AfterFunc call, with the duration until the earliest deadline.
+func New(clk quartz.Clock, tags ...string) clock.Clock {
+	tags = append([]string{Tag}, tags...)
+	a := &adapter{clk: clk, tags: tags, timers: clock.New()}
+	if m, ok := clk.(*quartz.Mock); ok {
+		mock := clock.NewMock()
+		mock.Set(m.Now(tags...))
+		a.mock = mock
+		a.timers = mock
+		m.BeforeAdvance(a.follow)
+	}
+	return a
+}
+
+type adapter struct {
+	clk  quartz.Clock
+	tags []string
+	// timers creates clock.Timers and clock.Tickers: the clock.Mock following clk, if clk is a
+	// Mock, otherwise the real clock.
+	timers clock.Clock
+	mock   *clock.Mock
+
+	// mu serializes following the Mock, and protects deadlines, the next deadline of each Timer and
+	// Ticker created on the clock.Mock that has not yet passed, and follower, the event scheduled on
+	// the Mock at followAt, the earliest of them.
+	mu        sync.Mutex
+	deadlines []deadline
+	follower  *quartz.Timer
+	followAt  time.Time
+}
+
+// deadline is the next deadline of a Timer, or of a Ticker, with its period.
+type deadline struct {
+	next   time.Time
+	period time.Duration
+}
+
+// track records the deadline of a Timer, or Ticker if period is positive, created on the clock.Mock,
+// then follows the Mock, to schedule the event for it if it is the earliest.
+func (a *adapter) track(next time.Time, period time.Duration, now time.Time) {
+	a.mu.Lock()
+	a.deadlines = append(a.deadlines, deadline{next: next, period: period})
+	a.mu.Unlock()
+	a.follow(now)
+}
+
+// follow moves the clock.Mock forward to now, the time of the quartz.Mock, firing its timers and
+// tickers, then reschedules the follower at the earliest remaining deadline.
+func (a *adapter) follow(now time.Time) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if now.After(a.mock.Now()) {
+		a.mock.Set(now)
+	}
+	var next time.Time
+	kept := a.deadlines[:0]
+	for _, d := range a.deadlines {
+		if !d.next.After(now) {
+			if d.period <= 0 {
+				continue
+			}
+			d.next = d.next.Add((now.Sub(d.next)/d.period + 1) * d.period)
+		}
+		kept = append(kept, d)
+		if next.IsZero() || d.next.Before(next) {
+			next = d.next
+		}
+	}
+	clear(a.deadlines[len(kept):])
+	a.deadlines = kept
+	if a.follower != nil && !next.IsZero() && next.Equal(a.followAt) {
+		return
+	}
+	if a.follower != nil {
+		a.follower.Stop(a.tags...)
+		a.follower = nil
+	}
+	if next.IsZero() {
+		return
+	}
+	a.followAt = next
+	a.follower = a.clk.AfterFunc(next.Sub(now), func() {
+		a.follow(a.clk.Now(a.tags...))
+	}, a.tags...)
+}
+
+func (a *adapter) After(d time.Duration) <-chan time.Time {
+	return a.clk.After(d, a.tags...)
+}
+
+func (a *adapter) AfterFunc(d time.Duration, f func()) *clock.Timer {
+	if a.mock == nil {
+		return a.timers.AfterFunc(d, f)
+	}
+	now := a.clk.Now(a.tags...)
+	a.follow(now)
+	t := a.mock.AfterFunc(d, f)
+	a.track(now.Add(d), 0, now)
+	return t
+}
+
+func (a *adapter) Now() time.Time {
+	return a.clk.Now(a.tags...)
+}
+
+func (a *adapter) Since(t time.Time) time.Duration {
+	return a.clk.Since(t, a.tags...)
+}
+
+func (a *adapter) Until(t time.Time) time.Duration {
+	return a.clk.Until(t, a.tags...)
+}
+
+func (a *adapter) Sleep(d time.Duration) {
+	a.clk.Sleep(d, a.tags...)
+}
+
+func (a *adapter) Tick(d time.Duration) <-chan time.Time {
+	if d <= 0 {
+		return nil
+	}
+	return a.Ticker(d).C
+}
+
+func (a *adapter) Ticker(d time.Duration) *clock.Ticker {
+	if a.mock == nil {
+		return a.timers.Ticker(d)
+	}
+	now := a.clk.Now(a.tags...)
+	a.follow(now)
+	t := a.mock.Ticker(d)
+	a.track(now.Add(d), d, now)
+	return t
+}
+
+func (a *adapter) Timer(d time.Duration) *clock.Timer {
+	if a.mock == nil {
+		return a.timers.Timer(d)
+	}
+	now := a.clk.Now(a.tags...)
+	a.follow(now)
+	t := a.mock.Timer(d)
+	a.track(now.Add(d), 0, now)
+	return t
+}
+
+func (a *adapter) WithDeadline(parent context.Context, d time.Time) (context.Context, context.CancelFunc) {
+	return a.clk.WithDeadline(parent, d, a.tags...)
+}
+
+func (a *adapter) WithTimeout(parent context.Context, t time.Duration) (context.Context, context.CancelFunc) {
+	return a.clk.WithTimeout(parent, t, a.tags...)
+}
+
+var _ clock.Clock = &adapter{}
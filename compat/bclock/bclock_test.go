@@ -0,0 +1,115 @@
+package bclock_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/coder/quartz"
+	"github.com/coder/quartz/compat/bclock"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mClock := quartz.NewMock(t)
+	clk := bclock.New(mClock)
+	start := mClock.Now()
+	if got := clk.Now(); !got.Equal(start) {
+		t.Fatalf("expected %s got %s", start, got)
+	}
+
+	tmr := clk.Timer(time.Second)
+	var called atomic.Bool
+	clk.AfterFunc(2*time.Second, func() { called.Store(true) })
+	tkr := clk.Ticker(time.Second)
+	defer tkr.Stop()
+
+	mClock.Advance(time.Second).MustWait(ctx)
+	select {
+	case got := <-tmr.C:
+		if want := start.Add(time.Second); !got.Equal(want) {
+			t.Fatalf("expected timer to fire at %s, got %s", want, got)
+		}
+	default:
+		t.Fatal("expected timer to fire")
+	}
+	if got := <-tkr.C; !got.Equal(start.Add(time.Second)) {
+		t.Fatalf("unexpected tick at %s", got)
+	}
+
+	mClock.Advance(time.Second).MustWait(ctx)
+	if got := <-tkr.C; !got.Equal(start.Add(2 * time.Second)) {
+		t.Fatalf("unexpected tick at %s", got)
+	}
+	// AfterFunc runs its function asynchronously.
+	for !called.Load() {
+		if ctx.Err() != nil {
+			t.Fatal("expected AfterFunc to be called")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if got := clk.Since(start); got != 2*time.Second {
+		t.Fatalf("expected 2s since start, got %s", got)
+	}
+}
+
+func TestNew_Trap(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mClock := quartz.NewMock(t)
+	clk := bclock.New(mClock)
+	trap := mClock.Trap().Now(bclock.Tag)
+	defer trap.Close()
+
+	go clk.Now()
+	trap.MustWait(ctx).MustRelease(ctx)
+
+	timerTrap := mClock.Trap().AfterFunc(bclock.Tag)
+	defer timerTrap.Close()
+	go clk.Timer(time.Minute)
+	trap.MustWait(ctx).MustRelease(ctx) // following the Mock
+	c := timerTrap.MustWait(ctx)
+	if c.Duration != time.Minute {
+		t.Fatalf("expected 1m timer, got %s", c.Duration)
+	}
+	c.MustRelease(ctx)
+}
+
+func TestNew_StopAndReset(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mClock := quartz.NewMock(t)
+	clk := bclock.New(mClock)
+	start := mClock.Now()
+
+	// a stopped timer doesn't fire when the Mock reaches its deadline, and leaves no events.
+	tmr := clk.Timer(time.Second)
+	tmr.Stop()
+	_, w := mClock.AdvanceNext()
+	w.MustWait(ctx)
+	select {
+	case got := <-tmr.C:
+		t.Fatalf("expected stopped timer not to fire, got %s", got)
+	default:
+	}
+	if d, ok := mClock.Peek(); ok {
+		t.Fatalf("expected no events after the stopped timer's deadline, got one in %s", d)
+	}
+
+	// a timer reset after firing fires at the start of the first advance past its new deadline,
+	// with its deadline as the time.
+	tmr.Reset(time.Minute)
+	mClock.Advance(time.Minute).MustWait(ctx)
+	mClock.Advance(time.Second).MustWait(ctx)
+	if got := <-tmr.C; !got.Equal(start.Add(time.Minute + time.Second)) {
+		t.Fatalf("unexpected timer fire at %s", got)
+	}
+}
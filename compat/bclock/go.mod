@@ -0,0 +1,10 @@
+module github.com/coder/quartz/compat/bclock
+
+go 1.23.9
+
+require (
+	github.com/benbjohnson/clock v1.3.5
+	github.com/coder/quartz v0.0.0
+)
+
+replace github.com/coder/quartz => ../../
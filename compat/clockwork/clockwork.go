@@ -0,0 +1,99 @@
+// Package clockwork adapts a quartz.Clock to the Clock interface of github.com/jonboulle/clockwork,
+// so that libraries written against clockwork share the virtual time of a quartz.Mock with the rest
+// of the code under test.
+package clockwork
+
+import (
+	"time"
+
+	upstream "github.com/jonboulle/clockwork"
+
+	"github.com/coder/quartz"
+)
+
+// Tag is the tag of every call made through the adapter, so that tests can trap the calls of
+// libraries using clockwork, e.g. mClock.Trap().NewTimer(clockwork.Tag).
+const Tag = "clockwork"
+
+// New returns a clockwork.Clock backed by clk. Every method, including those of the returned
+// Timers and Tickers, is a call on clk with Tag followed by the given tags, so it can be trapped
+// like a native call.
+//
+// clockwork.WithTimeout and clockwork.WithDeadline only use virtual time for clockwork's own
+// FakeClock, so code under test should use clk's WithTimeout and WithDeadline instead.
+func New(clk quartz.Clock, tags ...string) upstream.Clock {
+	return &adapter{clk: clk, tags: append([]string{Tag}, tags...)}
+}
+
+type adapter struct {
+	clk  quartz.Clock
+	tags []string
+}
+
+func (a *adapter) After(d time.Duration) <-chan time.Time {
+	return a.clk.After(d, a.tags...)
+}
+
+func (a *adapter) Sleep(d time.Duration) {
+	a.clk.Sleep(d, a.tags...)
+}
+
+func (a *adapter) Now() time.Time {
+	return a.clk.Now(a.tags...)
+}
+
+func (a *adapter) Since(t time.Time) time.Duration {
+	return a.clk.Since(t, a.tags...)
+}
+
+func (a *adapter) Until(t time.Time) time.Duration {
+	return a.clk.Until(t, a.tags...)
+}
+
+func (a *adapter) NewTicker(d time.Duration) upstream.Ticker {
+	return &ticker{t: a.clk.NewTicker(d, a.tags...), tags: a.tags}
+}
+
+func (a *adapter) NewTimer(d time.Duration) upstream.Timer {
+	return &timer{t: a.clk.NewTimer(d, a.tags...), tags: a.tags}
+}
+
+func (a *adapter) AfterFunc(d time.Duration, f func()) upstream.Timer {
+	return &timer{t: a.clk.AfterFunc(d, f, a.tags...), tags: a.tags}
+}
+
+type ticker struct {
+	t    *quartz.Ticker
+	tags []string
+}
+
+func (t *ticker) Chan() <-chan time.Time {
+	return t.t.C
+}
+
+func (t *ticker) Reset(d time.Duration) {
+	t.t.Reset(d, t.tags...)
+}
+
+func (t *ticker) Stop() {
+	t.t.Stop(t.tags...)
+}
+
+type timer struct {
+	t    *quartz.Timer
+	tags []string
+}
+
+func (t *timer) Chan() <-chan time.Time {
+	return t.t.C
+}
+
+func (t *timer) Reset(d time.Duration) bool {
+	return t.t.Reset(d, t.tags...)
+}
+
+func (t *timer) Stop() bool {
+	return t.t.Stop(t.tags...)
+}
+
+var _ upstream.Clock = &adapter{}
@@ -0,0 +1,47 @@
+package clockwork_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/coder/quartz"
+	"github.com/coder/quartz/compat/clockwork"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mClock := quartz.NewMock(t)
+	clk := clockwork.New(mClock, "lib")
+	start := mClock.Now()
+
+	trap := mClock.Trap().NewTimer(clockwork.Tag, "lib")
+	defer trap.Close()
+	tmrCh := make(chan interface{ Chan() <-chan time.Time }, 1)
+	go func() {
+		tmrCh <- clk.NewTimer(time.Second)
+	}()
+	call := trap.MustWait(ctx)
+	if call.Duration != time.Second {
+		t.Fatalf("expected 1s timer, got %s", call.Duration)
+	}
+	call.MustRelease(ctx)
+	tmr := <-tmrCh
+
+	tkr := clk.NewTicker(time.Second)
+	defer tkr.Stop()
+
+	mClock.Advance(time.Second).MustWait(ctx)
+	if got := <-tmr.Chan(); !got.Equal(start.Add(time.Second)) {
+		t.Fatalf("unexpected timer fire at %s", got)
+	}
+	if got := <-tkr.Chan(); !got.Equal(start.Add(time.Second)) {
+		t.Fatalf("unexpected tick at %s", got)
+	}
+	if got := clk.Since(start); got != time.Second {
+		t.Fatalf("expected 1s since start, got %s", got)
+	}
+}
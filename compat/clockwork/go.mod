@@ -0,0 +1,10 @@
+module github.com/coder/quartz/compat/clockwork
+
+go 1.23.9
+
+require (
+	github.com/coder/quartz v0.0.0
+	github.com/jonboulle/clockwork v0.5.0
+)
+
+replace github.com/coder/quartz => ../../
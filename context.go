@@ -0,0 +1,19 @@
+package quartz
+
+import "context"
+
+type clockContextKey struct{}
+
+// NewContext returns a copy of ctx that carries clk. Code that doesn't have a Clock threaded
+// through to it explicitly can retrieve it with FromContext.
+func NewContext(ctx context.Context, clk Clock) context.Context {
+	return context.WithValue(ctx, clockContextKey{}, clk)
+}
+
+// FromContext returns the Clock carried by ctx, or a real clock if ctx doesn't carry one.
+func FromContext(ctx context.Context) Clock {
+	if clk, ok := ctx.Value(clockContextKey{}).(Clock); ok {
+		return clk
+	}
+	return NewReal()
+}
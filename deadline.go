@@ -0,0 +1,149 @@
+package quartz
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// WithTimeout returns a copy of ctx that is canceled once the clock has been advanced by d, or
+// when the returned cancel function is called, or when ctx is done, whichever happens first. The
+// deadline is scheduled as an event, so it is visible to Peek and fired by Advance, and the call
+// can be trapped.
+//
+// Unlike context.WithTimeout, the deadline of ctx is not taken into account, since it is usually
+// measured in real time.
+func (m *Mock) WithTimeout(ctx context.Context, d time.Duration, tags ...string) (context.Context, context.CancelFunc) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	c := newCall(clockFunctionWithTimeout, m.contextTagsLocked(ctx, tags), withDuration(d))
+	m.matchCallLocked(c)
+	defer close(c.complete)
+	return m.withDeadlineLocked(ctx, c, addDuration(m.cur, m.scheduleDurationLocked(c)))
+}
+
+// WithDeadline is like WithTimeout, but is canceled once the clock reaches the deadline t.
+func (m *Mock) WithDeadline(ctx context.Context, t time.Time, tags ...string) (context.Context, context.CancelFunc) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	c := newCall(clockFunctionWithDeadline, m.contextTagsLocked(ctx, tags), withTime(t))
+	m.matchCallLocked(c)
+	defer close(c.complete)
+	return m.withDeadlineLocked(ctx, c, t)
+}
+
+func (m *Mock) withDeadlineLocked(
+	ctx context.Context, c *apiCall, deadline time.Time,
+) (context.Context, context.CancelFunc) {
+	dc := newDeadlineContext(ctx, deadline)
+	e := &deadlineEvent{
+		mock: m,
+		kind: c.fn,
+		tags: c.Tags,
+		d:    deadline.Sub(m.cur),
+		nxt:  deadline,
+		ctx:  dc,
+	}
+	if !deadline.After(m.cur) {
+		dc.cancel(context.DeadlineExceeded)
+		return dc, func() {}
+	}
+	m.addEventLocked(e)
+	// cancel dc, and remove the deadline from the schedule, if ctx is done first.
+	e.stop = context.AfterFunc(ctx, func() {
+		dc.cancel(ctx.Err())
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.removeEventLocked(e)
+	})
+	return dc, func() {
+		m.mu.Lock()
+		e.stop()
+		m.removeEventLocked(e)
+		m.mu.Unlock()
+		dc.cancel(context.Canceled)
+	}
+}
+
+// deadlineEvent is the event that cancels a context returned by WithTimeout or WithDeadline.
+type deadlineEvent struct {
+	mock *Mock
+	kind clockFunction
+	tags []string
+	d    time.Duration
+	nxt  time.Time
+	ctx  *deadlineContext
+	// stop stops propagating the cancellation of the parent context.
+	stop func() bool
+}
+
+func (e *deadlineEvent) next() time.Time {
+	return e.nxt
+}
+
+func (e *deadlineEvent) fire(time.Time) {
+	e.mock.mu.Lock()
+	e.mock.removeEventLocked(e)
+	e.stop()
+	e.mock.mu.Unlock()
+	e.ctx.cancel(context.DeadlineExceeded)
+}
+
+func (e *deadlineEvent) info() EventInfo {
+	return EventInfo{Kind: e.kind.String(), Tags: e.tags, Deadline: e.nxt, Duration: e.d}
+}
+
+// deadlineContext is a context whose deadline is measured on a Clock, rather than real time. It
+// carries the values of its parent, and is canceled when its parent is, but unlike a context built
+// on context.WithCancel, its own error is context.DeadlineExceeded once the deadline passes, so
+// contexts derived from it report that error too.
+type deadlineContext struct {
+	parent   context.Context
+	deadline time.Time
+	done     chan struct{}
+
+	mu  sync.Mutex
+	err error
+}
+
+func newDeadlineContext(parent context.Context, deadline time.Time) *deadlineContext {
+	return &deadlineContext{parent: parent, deadline: deadline, done: make(chan struct{})}
+}
+
+// cancel cancels the context with err, unless it is already canceled.
+func (c *deadlineContext) cancel(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return
+	}
+	c.err = err
+	close(c.done)
+}
+
+// Deadline returns the earlier of the virtual deadline and the parent's deadline.
+func (c *deadlineContext) Deadline() (time.Time, bool) {
+	if d, ok := c.parent.Deadline(); ok && d.Before(c.deadline) {
+		return d, true
+	}
+	return c.deadline, true
+}
+
+func (c *deadlineContext) Done() <-chan struct{} {
+	return c.done
+}
+
+func (c *deadlineContext) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+func (c *deadlineContext) Value(key any) any {
+	return c.parent.Value(key)
+}
+
+func (c *deadlineContext) String() string {
+	return fmt.Sprintf("%v.WithDeadline(%s)", c.parent, c.deadline.Format(time.RFC3339Nano))
+}
@@ -0,0 +1,132 @@
+package quartz
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+)
+
+// DelayQueue is a queue of items that each become ready at a given time, as measured by a Clock.
+// Pop blocks until the earliest item is ready, so work-scheduling code built on a DelayQueue is
+// deterministic when tested with a Mock.
+//
+// Each time Pop waits, it calls Until and NewTimer on the Clock with the tags passed to
+// NewDelayQueue, so tests can trap these calls to know when Pop is waiting for an item.
+type DelayQueue[T any] struct {
+	clock Clock
+	tags  []string
+
+	mu    sync.Mutex
+	items delayItems[T]
+	seq   uint64
+	// changed is closed and replaced whenever an item is pushed, to wake up waiting Pop calls.
+	changed chan struct{}
+}
+
+// NewDelayQueue returns an empty DelayQueue using the given Clock.
+func NewDelayQueue[T any](clk Clock, tags ...string) *DelayQueue[T] {
+	return &DelayQueue[T]{
+		clock:   clk,
+		tags:    tags,
+		changed: make(chan struct{}),
+	}
+}
+
+// Push adds an item to the queue that becomes ready at readyAt. Items with the same ready time
+// are popped in the order they were pushed.
+func (q *DelayQueue[T]) Push(item T, readyAt time.Time) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	heap.Push(&q.items, &delayItem[T]{value: item, at: readyAt, seq: q.seq})
+	q.seq++
+	close(q.changed)
+	q.changed = make(chan struct{})
+}
+
+// PushAfter adds an item to the queue that becomes ready after duration d.
+func (q *DelayQueue[T]) PushAfter(item T, d time.Duration) {
+	q.Push(item, q.clock.Now(q.tags...).Add(d))
+}
+
+// Pop removes and returns the earliest item from the queue, blocking until it is ready or the
+// context expires.
+func (q *DelayQueue[T]) Pop(ctx context.Context) (T, error) {
+	for {
+		q.mu.Lock()
+		changed := q.changed
+		if len(q.items) == 0 {
+			q.mu.Unlock()
+			select {
+			case <-changed:
+				continue
+			case <-ctx.Done():
+				var zero T
+				return zero, ctx.Err()
+			}
+		}
+		head := q.items[0]
+		q.mu.Unlock()
+
+		d := q.clock.Until(head.at, q.tags...)
+		if d <= 0 {
+			q.mu.Lock()
+			if len(q.items) > 0 && q.items[0] == head {
+				heap.Pop(&q.items)
+				q.mu.Unlock()
+				return head.value, nil
+			}
+			// another Pop took the item first
+			q.mu.Unlock()
+			continue
+		}
+		tmr := q.clock.NewTimer(d, q.tags...)
+		select {
+		case <-tmr.C:
+		case <-changed:
+			tmr.Stop(q.tags...)
+		case <-ctx.Done():
+			tmr.Stop(q.tags...)
+			var zero T
+			return zero, ctx.Err()
+		}
+	}
+}
+
+// Len returns the number of items in the queue, whether or not they are ready.
+func (q *DelayQueue[T]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.items)
+}
+
+type delayItem[T any] struct {
+	value T
+	at    time.Time
+	seq   uint64
+}
+
+// delayItems implements heap.Interface, ordered by ready time then push order.
+type delayItems[T any] []*delayItem[T]
+
+func (d delayItems[T]) Len() int { return len(d) }
+
+func (d delayItems[T]) Less(i, j int) bool {
+	if d[i].at.Equal(d[j].at) {
+		return d[i].seq < d[j].seq
+	}
+	return d[i].at.Before(d[j].at)
+}
+
+func (d delayItems[T]) Swap(i, j int) { d[i], d[j] = d[j], d[i] }
+
+func (d *delayItems[T]) Push(x any) { *d = append(*d, x.(*delayItem[T])) }
+
+func (d *delayItems[T]) Pop() any {
+	old := *d
+	n := len(old)
+	it := old[n-1]
+	old[n-1] = nil
+	*d = old[:n-1]
+	return it
+}
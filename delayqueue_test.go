@@ -0,0 +1,63 @@
+package quartz_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/coder/quartz"
+)
+
+func TestDelayQueue(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mClock := quartz.NewMock(t)
+	trap := mClock.Trap().NewTimer("dq")
+	defer trap.Close()
+
+	q := quartz.NewDelayQueue[string](mClock, "dq")
+	q.PushAfter("b", 2*time.Second)
+	q.PushAfter("c", 2*time.Second)
+
+	popped := make(chan string)
+	go func() {
+		for i := 0; i < 3; i++ {
+			v, err := q.Pop(ctx)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			popped <- v
+		}
+	}()
+
+	c := trap.MustWait(ctx)
+	c.MustRelease(ctx)
+	if c.Duration != 2*time.Second {
+		t.Fatalf("expected 2s got %s", c.Duration)
+	}
+	// pushing an earlier item wakes up Pop, which waits on the new head.
+	q.PushAfter("a", time.Second)
+	c = trap.MustWait(ctx)
+	c.MustRelease(ctx)
+	if c.Duration != time.Second {
+		t.Fatalf("expected 1s got %s", c.Duration)
+	}
+
+	mClock.Advance(time.Second).MustWait(ctx)
+	if v := <-popped; v != "a" {
+		t.Fatalf("expected a got %s", v)
+	}
+	trap.MustWait(ctx).MustRelease(ctx)
+	mClock.Advance(time.Second).MustWait(ctx)
+	for _, want := range []string{"b", "c"} {
+		if v := <-popped; v != want {
+			t.Fatalf("expected %s got %s", want, v)
+		}
+	}
+	if q.Len() != 0 {
+		t.Fatalf("expected empty queue, got %d", q.Len())
+	}
+}
@@ -0,0 +1,72 @@
+package quartz
+
+// DropPolicy determines what a Mock does when a Ticker ticks while the previous tick is still
+// waiting to be read from its channel, so that the new tick is dropped. Like tickers from the
+// time package, mocked tickers only hold a single pending tick.
+type DropPolicy int
+
+const (
+	// DropSilently drops the tick without comment. This is the default.
+	DropSilently DropPolicy = iota
+	// DropLog drops the tick and logs it.
+	DropLog
+	// DropFail drops the tick and fails the test. This is useful for catching consumers that
+	// fall behind their tickers, which the other policies can mask.
+	DropFail
+)
+
+// WithDropPolicy sets what the Mock does when a Ticker tick is dropped because its channel has no
+// reader and the previous tick is still pending. Regardless of policy, dropped ticks are counted
+// and reported by DroppedTicks.
+func (m *Mock) WithDropPolicy(p DropPolicy) *Mock {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.dropPolicy = p
+	return m
+}
+
+// DroppedTicks returns the number of Ticker ticks the Mock has dropped because the previous tick
+// had not been read.
+func (m *Mock) DroppedTicks() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.droppedTicks
+}
+
+// WithSyncTicks causes tickers created by NewTicker with all of the given tags to deliver ticks
+// synchronously: the AdvanceWaiter of the advance that triggered a tick doesn't complete until the
+// tick has been received from the ticker's channel, or the ticker is stopped. This turns a
+// consumer that misses a tick from silent data loss into an AdvanceWaiter that never completes,
+// whose Pending events name the ticker. With no tags, it applies to all tickers.
+//
+// It can be called more than once to apply to tickers with different sets of tags. Only tickers
+// created after the call are affected.
+func (m *Mock) WithSyncTicks(tags ...string) *Mock {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.syncTicks = append(m.syncTicks, tags)
+	return m
+}
+
+// syncTicksLocked returns whether a ticker with the given tags should deliver ticks synchronously.
+func (m *Mock) syncTicksLocked(tags []string) bool {
+	for _, want := range m.syncTicks {
+		if containsAll(tags, want) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Mock) dropTickLocked(t *Ticker) {
+	m.droppedTicks++
+	if m.testOver {
+		return
+	}
+	switch m.dropPolicy {
+	case DropLog:
+		m.logfTaggedLocked(t.tags, "dropped tick of NewTicker(%s, %v); previous tick unread", t.d, t.tags)
+	case DropFail:
+		m.tb.Errorf("Mock Clock - dropped tick of NewTicker(%s, %v); previous tick unread", t.d, t.tags)
+	}
+}
@@ -0,0 +1,59 @@
+package quartz
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// DualRunOptions configures DualRun.
+type DualRunOptions struct {
+	// Scale is how many times faster than real time the real run goes, see NewScaledClock. Zero
+	// means 1, i.e. real time.
+	Scale float64
+	// Settle is how long the code under test must be idle before the mock run advances to the
+	// next event, see Mock.AutoAdvance. Zero means 10ms.
+	Settle time.Duration
+	// Timeout bounds each run, in real time. Zero means 30s.
+	Timeout time.Duration
+}
+
+// DualRun runs workload twice, as the subtests "real" and "mock": once on the real Clock, sped up
+// by Scale, and once on a Mock that advances automatically. It fails the test if the results of
+// the two runs differ, according to reflect.DeepEqual, and returns them. This validates that tests
+// using the Mock aren't hiding behavior that only shows up with real timing.
+//
+// The workload should return externally observable results, like the values it computed or the
+// order of its outputs, rather than timestamps, which differ between the runs. Its context is
+// canceled when the run times out.
+func DualRun[R any](
+	t *testing.T, opts DualRunOptions, workload func(ctx context.Context, clk Clock) R,
+) (realResult, mockResult R) {
+	t.Helper()
+	if opts.Scale == 0 {
+		opts.Scale = 1
+	}
+	if opts.Settle == 0 {
+		opts.Settle = 10 * time.Millisecond
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = 30 * time.Second
+	}
+	t.Run("real", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
+		defer cancel()
+		realResult = workload(ctx, NewScaledClock(NewReal(), opts.Scale))
+	})
+	t.Run("mock", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
+		defer cancel()
+		mClock := NewMock(t)
+		defer mClock.AutoAdvance(ctx, opts.Settle)()
+		mockResult = workload(ctx, mClock)
+	})
+	if !reflect.DeepEqual(realResult, mockResult) {
+		t.Errorf("results differ between real and mock runs:\n\treal: %+v\n\tmock: %+v", realResult, mockResult)
+	}
+	return realResult, mockResult
+}
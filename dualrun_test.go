@@ -0,0 +1,98 @@
+package quartz_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/coder/quartz"
+)
+
+func TestDualRun(t *testing.T) {
+	t.Parallel()
+	realResult, mockResult := quartz.DualRun(t, quartz.DualRunOptions{Scale: 50},
+		func(ctx context.Context, clk quartz.Clock) []string {
+			var mu sync.Mutex
+			var order []string
+			var wg sync.WaitGroup
+			for _, job := range []struct {
+				name string
+				d    time.Duration
+			}{{"c", 3 * time.Second}, {"a", time.Second}, {"b", 2 * time.Second}} {
+				wg.Add(1)
+				clk.AfterFunc(job.d, func() {
+					defer wg.Done()
+					mu.Lock()
+					defer mu.Unlock()
+					order = append(order, job.name)
+				})
+			}
+			wg.Wait()
+			return order
+		})
+	if len(realResult) != 3 || realResult[0] != "a" || mockResult[2] != "c" {
+		t.Fatalf("unexpected results %v, %v", realResult, mockResult)
+	}
+}
+
+func TestNewScaledClock(t *testing.T) {
+	t.Parallel()
+	clk := quartz.NewScaledClock(quartz.NewReal(), 1000)
+	start := clk.Now()
+	realStart := time.Now()
+	clk.Sleep(10 * time.Second)
+	if elapsed := time.Since(realStart); elapsed > 5*time.Second {
+		t.Fatalf("expected scaled sleep to take about 10ms, took %s", elapsed)
+	}
+	if d := clk.Since(start); d < 10*time.Second {
+		t.Fatalf("expected at least 10s of scaled time to pass, got %s", d)
+	}
+}
+
+func TestFreezeAll(t *testing.T) {
+	t.Parallel()
+	a := quartz.NewScaledClock(quartz.NewReal(), 1000)
+	b := quartz.NewScaledClock(quartz.NewReal(), 10)
+	quartz.RegisterFreezable(t, a, b)
+
+	quartz.FreezeAll(t)
+	aFrozen, bFrozen := a.Now(), b.Now()
+	time.Sleep(10 * time.Millisecond)
+	if !a.Now().Equal(aFrozen) || !b.Now().Equal(bFrozen) {
+		t.Fatal("expected frozen clocks not to advance")
+	}
+	quartz.ResumeAll(t)
+
+	// a advances 1000 times faster than real time, so if the 10ms pause had counted it would have
+	// advanced by at least 10s.
+	if d := a.Since(aFrozen); d >= 10*time.Second {
+		t.Fatalf("expected time spent frozen not to count, got %s", d)
+	}
+	time.Sleep(time.Millisecond)
+	if !a.Now().After(aFrozen) {
+		t.Fatal("expected resumed clock to advance")
+	}
+}
+
+func TestFreezeAll_Timers(t *testing.T) {
+	t.Parallel()
+	clk := quartz.NewScaledClock(quartz.NewReal(), 1)
+	quartz.RegisterFreezable(t, clk)
+
+	tmr := clk.NewTimer(20 * time.Millisecond)
+	fired := make(chan struct{})
+	clk.AfterFunc(20*time.Millisecond, func() { close(fired) })
+	quartz.FreezeAll(t)
+	time.Sleep(50 * time.Millisecond)
+	select {
+	case <-tmr.C:
+		t.Fatal("expected timer not to fire while frozen")
+	case <-fired:
+		t.Fatal("expected AfterFunc not to be called while frozen")
+	default:
+	}
+	quartz.ResumeAll(t)
+	<-tmr.C
+	<-fired
+}
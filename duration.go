@@ -0,0 +1,46 @@
+package quartz
+
+import (
+	"time"
+)
+
+// maxTime is the latest time representable by time.Time.
+var maxTime = time.Unix(1<<63-62135596801, 999999999)
+
+// addDuration returns t+d, saturating at the latest representable time rather than overflowing,
+// so that events scheduled with very large durations are simply never reached.
+func addDuration(t time.Time, d time.Duration) time.Time {
+	r := t.Add(d)
+	if d > 0 && r.Before(t) {
+		return maxTime
+	}
+	return r
+}
+
+// WithMaxDuration causes the Mock to fail the test if code under test schedules a timer, ticker or
+// Reset with a duration greater than limit. Unreasonably large durations usually indicate a unit
+// conversion bug, like passing a number of seconds where a time.Duration in nanoseconds is
+// expected, or multiplying two Durations. A limit of zero, the default, disables the check.
+//
+// Without this check, the Mock handles durations up to math.MaxInt64 without overflowing, even if
+// the resulting deadline is beyond the year 9999: deadlines are clamped to the latest time that
+// time.Time can represent, and such events never fire unless the clock is advanced to them.
+func (m *Mock) WithMaxDuration(limit time.Duration) *Mock {
+	if limit < 0 {
+		panic("WithMaxDuration called with negative duration")
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.maxDuration = limit
+	return m
+}
+
+// checkDurationLocked fails the test if the duration of the call exceeds the maximum set by
+// WithMaxDuration.
+func (m *Mock) checkDurationLocked(c *apiCall) {
+	if m.maxDuration == 0 || c.Duration <= m.maxDuration {
+		return
+	}
+	m.tb.Errorf("Mock Clock - %s: duration exceeds maximum %s; this often indicates a unit conversion bug",
+		c, m.maxDuration)
+}
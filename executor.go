@@ -0,0 +1,31 @@
+package quartz
+
+// WithAfterFuncExecutor causes the Mock to run functions passed to AfterFunc by calling exec,
+// rather than directly on the goroutine that fires the timer. This supports code that runs
+// callbacks on a single-threaded event loop, and lets tests take control of when callbacks run,
+// e.g. by queueing them and running them one at a time on the test goroutine.
+//
+// exec is called once the timer fires, and the AdvanceWaiter waits for exec to return, but not for
+// the function it was passed, unless exec runs it inline.
+func (m *Mock) WithAfterFuncExecutor(exec func(fn func())) *Mock {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.afterFuncExec = exec
+	return m
+}
+
+// NewRealWithAfterFuncExecutor returns a real Clock that runs functions passed to AfterFunc by
+// calling exec, rather than on a new goroutine. exec is called on the goroutine the time package
+// uses to run AfterFunc callbacks, so it should hand fn off to the event loop rather than block.
+func NewRealWithAfterFuncExecutor(exec func(fn func())) Clock {
+	return realClock{afterFuncExec: exec}
+}
+
+// execAfterFunc runs f, the function passed to AfterFunc, using exec if set.
+func execAfterFunc(exec func(fn func()), f func()) {
+	if exec == nil {
+		f()
+		return
+	}
+	exec(f)
+}
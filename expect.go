@@ -0,0 +1,194 @@
+package quartz
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// Expecter declares expected calls to a Mock, see Mock.Expect.
+type Expecter struct {
+	mock *Mock
+}
+
+// Expectation is an expected call to a Mock. By default, the call is expected exactly once.
+type Expectation struct {
+	mock  *Mock
+	fn    clockFunction
+	d     *time.Duration // the expected duration argument, if any
+	tags  []string
+	times int
+
+	// matched is the number of calls that met the expectation, protected by mock.mu.
+	matched int
+}
+
+// Expect returns an Expecter, for declaring the calls the code under test is expected to make,
+// rather than trapping them. This suits tests that only care about the number of clock
+// interactions, not about gating them:
+//
+//	mClock.Expect().NewTimer(time.Minute, "reconnect").Times(3)
+//	// ... run the code under test ...
+//	mClock.AssertExpectations(t)
+//
+// A call meets an expectation if it is to the same method, its tags include all of the
+// expectation's tags, and its duration argument, if any, is the expected one.
+func (m *Mock) Expect() Expecter {
+	return Expecter{mock: m}
+}
+
+func (e Expecter) NewTimer(d time.Duration, tags ...string) *Expectation {
+	return e.newExpectation(clockFunctionNewTimer, &d, tags)
+}
+
+func (e Expecter) NewTimerInto(d time.Duration, tags ...string) *Expectation {
+	return e.newExpectation(clockFunctionNewTimerInto, &d, tags)
+}
+
+func (e Expecter) After(d time.Duration, tags ...string) *Expectation {
+	return e.newExpectation(clockFunctionAfter, &d, tags)
+}
+
+func (e Expecter) SleepContext(d time.Duration, tags ...string) *Expectation {
+	return e.newExpectation(clockFunctionSleepContext, &d, tags)
+}
+
+func (e Expecter) WithTimeout(d time.Duration, tags ...string) *Expectation {
+	return e.newExpectation(clockFunctionWithTimeout, &d, tags)
+}
+
+// WithDeadline expects a call to WithDeadline with any deadline.
+func (e Expecter) WithDeadline(tags ...string) *Expectation {
+	return e.newExpectation(clockFunctionWithDeadline, nil, tags)
+}
+
+func (e Expecter) AfterFunc(d time.Duration, tags ...string) *Expectation {
+	return e.newExpectation(clockFunctionAfterFunc, &d, tags)
+}
+
+func (e Expecter) Sleep(d time.Duration, tags ...string) *Expectation {
+	return e.newExpectation(clockFunctionSleep, &d, tags)
+}
+
+func (e Expecter) TimerReset(d time.Duration, tags ...string) *Expectation {
+	return e.newExpectation(clockFunctionTimerReset, &d, tags)
+}
+
+func (e Expecter) TimerStop(tags ...string) *Expectation {
+	return e.newExpectation(clockFunctionTimerStop, nil, tags)
+}
+
+func (e Expecter) NewTicker(d time.Duration, tags ...string) *Expectation {
+	return e.newExpectation(clockFunctionNewTicker, &d, tags)
+}
+
+func (e Expecter) TickerReset(d time.Duration, tags ...string) *Expectation {
+	return e.newExpectation(clockFunctionTickerReset, &d, tags)
+}
+
+func (e Expecter) TickerStop(tags ...string) *Expectation {
+	return e.newExpectation(clockFunctionTickerStop, nil, tags)
+}
+
+func (e Expecter) TickerFunc(d time.Duration, tags ...string) *Expectation {
+	return e.newExpectation(clockFunctionTickerFunc, &d, tags)
+}
+
+func (e Expecter) TickerFuncWait(tags ...string) *Expectation {
+	return e.newExpectation(clockFunctionTickerFuncWait, nil, tags)
+}
+
+func (e Expecter) TickerFuncStop(tags ...string) *Expectation {
+	return e.newExpectation(clockFunctionTickerFuncStop, nil, tags)
+}
+
+func (e Expecter) TimerFunc(d time.Duration, tags ...string) *Expectation {
+	return e.newExpectation(clockFunctionTimerFunc, &d, tags)
+}
+
+func (e Expecter) TimerFuncWait(tags ...string) *Expectation {
+	return e.newExpectation(clockFunctionTimerFuncWait, nil, tags)
+}
+
+// AdaptiveTickerFunc expects calls to AdaptiveTickerFunc with any interval. Note that, as for
+// traps, each interval the AdaptiveTickerFunc schedules counts as a call.
+func (e Expecter) AdaptiveTickerFunc(tags ...string) *Expectation {
+	return e.newExpectation(clockFunctionAdaptiveTickerFunc, nil, tags)
+}
+
+func (e Expecter) AdaptiveTickerFuncWait(tags ...string) *Expectation {
+	return e.newExpectation(clockFunctionAdaptiveTickerFuncWait, nil, tags)
+}
+
+func (e Expecter) AdaptiveTickerFuncStop(tags ...string) *Expectation {
+	return e.newExpectation(clockFunctionAdaptiveTickerFuncStop, nil, tags)
+}
+
+func (e Expecter) Now(tags ...string) *Expectation {
+	return e.newExpectation(clockFunctionNow, nil, tags)
+}
+
+func (e Expecter) Since(tags ...string) *Expectation {
+	return e.newExpectation(clockFunctionSince, nil, tags)
+}
+
+func (e Expecter) Until(tags ...string) *Expectation {
+	return e.newExpectation(clockFunctionUntil, nil, tags)
+}
+
+func (e Expecter) newExpectation(fn clockFunction, d *time.Duration, tags []string) *Expectation {
+	m := e.mock
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	x := &Expectation{mock: m, fn: fn, d: d, tags: tags, times: 1}
+	m.expectations = append(m.expectations, x)
+	return x
+}
+
+// Times sets the number of calls expected, and returns the Expectation. Zero asserts that the call
+// is never made.
+func (x *Expectation) Times(n int) *Expectation {
+	x.mock.mu.Lock()
+	defer x.mock.mu.Unlock()
+	x.times = n
+	return x
+}
+
+func (x *Expectation) String() string {
+	if x.d != nil {
+		return fmt.Sprintf("%s(%s, %v)", x.fn, *x.d, x.tags)
+	}
+	return fmt.Sprintf("%s(%v)", x.fn, x.tags)
+}
+
+func (x *Expectation) matches(c *apiCall) bool {
+	if x.fn != c.fn || !containsAll(c.Tags, x.tags) {
+		return false
+	}
+	return x.d == nil || *x.d == c.Duration
+}
+
+// recordExpectationsLocked counts the call against the expectations it meets.
+func (m *Mock) recordExpectationsLocked(c *apiCall) {
+	for _, x := range m.expectations {
+		if x.matches(c) {
+			x.matched++
+		}
+	}
+}
+
+// AssertExpectations fails the test if any of the expectations declared with Expect was not met
+// the expected number of times. It returns whether all the expectations were met.
+func (m *Mock) AssertExpectations(tb testing.TB) bool {
+	tb.Helper()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ok := true
+	for _, x := range m.expectations {
+		if x.matched != x.times {
+			tb.Errorf("expected %s to be called %d times, but it was called %d times", x, x.times, x.matched)
+			ok = false
+		}
+	}
+	return ok
+}
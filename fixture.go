@@ -0,0 +1,94 @@
+package quartz
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"time"
+)
+
+// ScheduledEvent describes a pending event of a Mock relative to the Mock's current time, so that
+// it can be saved with SaveSchedule and recreated with LoadSchedule in other tests.
+type ScheduledEvent struct {
+	// Kind is the name of the Clock method that created the event, e.g. "NewTimer".
+	Kind string
+	// Tags are the tags the event was created with.
+	Tags []string
+	// In is how long until the event fires.
+	In time.Duration
+	// Duration is the duration the event was scheduled with, or for tickers, their period.
+	Duration time.Duration
+}
+
+// scheduleFile is the JSON format of a saved schedule. Durations are formatted as strings, so that
+// fixtures are readable and editable by hand.
+type scheduleFile struct {
+	Events []scheduleFileEvent `json:"events"`
+}
+
+type scheduleFileEvent struct {
+	Kind     string   `json:"kind"`
+	Tags     []string `json:"tags,omitempty"`
+	In       string   `json:"in"`
+	Duration string   `json:"duration"`
+}
+
+// SaveSchedule writes the pending events of the Mock to w as JSON, in the order they will fire,
+// with deadlines relative to the current time. Together with LoadSchedule, this allows a schedule
+// built up by one test, e.g. a system at steady state with hundreds of active sessions, to be used
+// as a starting fixture by others.
+func (m *Mock) SaveSchedule(w io.Writer) error {
+	m.mu.Lock()
+	now, events := m.cur, m.peekNLocked(math.MaxInt)
+	m.mu.Unlock()
+	var f scheduleFile
+	for _, e := range events {
+		f.Events = append(f.Events, scheduleFileEvent{
+			Kind:     e.Kind,
+			Tags:     e.Tags,
+			In:       e.Deadline.Sub(now).String(),
+			Duration: e.Duration.String(),
+		})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(f)
+}
+
+// LoadSchedule reads a schedule written by SaveSchedule from r, and calls build for each of its
+// events, in the order they will fire. Since the functions and channels of the original events
+// cannot be saved, build is responsible for recreating each event on the Mock with the behavior the
+// test needs, typically by calling AfterFunc or At on a Mock with the event's In and Tags:
+//
+//	err := quartz.LoadSchedule(f, func(e quartz.ScheduledEvent) error {
+//		sessions.Restore(e.Tags, e.In)
+//		return nil
+//	})
+//
+// It returns the first error from decoding the schedule or from build.
+func LoadSchedule(r io.Reader, build func(e ScheduledEvent) error) error {
+	var f scheduleFile
+	if err := json.NewDecoder(r).Decode(&f); err != nil {
+		return fmt.Errorf("decode schedule: %w", err)
+	}
+	events := make([]ScheduledEvent, 0, len(f.Events))
+	for i, fe := range f.Events {
+		in, err := time.ParseDuration(fe.In)
+		if err != nil {
+			return fmt.Errorf("event %d: parse in: %w", i, err)
+		}
+		d, err := time.ParseDuration(fe.Duration)
+		if err != nil {
+			return fmt.Errorf("event %d: parse duration: %w", i, err)
+		}
+		events = append(events, ScheduledEvent{Kind: fe.Kind, Tags: slices.Clone(fe.Tags), In: in, Duration: d})
+	}
+	for _, e := range events {
+		if err := build(e); err != nil {
+			return fmt.Errorf("build %s(%v) in %s: %w", e.Kind, e.Tags, e.In, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package quartz
+
+import (
+	"sync"
+	"testing"
+)
+
+// freezable is a Clock decorator over real time that can be paused, see FreezeAll.
+type freezable interface {
+	freeze()
+	resume()
+	// resumeAll undoes every nested freeze.
+	resumeAll()
+}
+
+var (
+	freezablesMu sync.Mutex
+	// freezables holds the clocks registered by each test, until it ends.
+	freezables = map[testing.TB][]freezable{}
+)
+
+// RegisterFreezable registers clocks created by NewScaledClock for FreezeAll and ResumeAll in the
+// test tb. The clocks are unregistered, and resumed if still frozen, when tb ends, so that parallel
+// tests freeze only their own clocks. It panics if a clock was not created by NewScaledClock.
+func RegisterFreezable(tb testing.TB, clocks ...Clock) {
+	tb.Helper()
+	fs := make([]freezable, 0, len(clocks))
+	for _, clk := range clocks {
+		f, ok := clk.(freezable)
+		if !ok {
+			panic("RegisterFreezable called with a Clock not created by NewScaledClock")
+		}
+		fs = append(fs, f)
+	}
+	freezablesMu.Lock()
+	defer freezablesMu.Unlock()
+	if _, ok := freezables[tb]; !ok {
+		tb.Cleanup(func() {
+			freezablesMu.Lock()
+			defer freezablesMu.Unlock()
+			for _, f := range freezables[tb] {
+				f.resumeAll()
+			}
+			delete(freezables, tb)
+		})
+	}
+	freezables[tb] = append(freezables[tb], fs...)
+}
+
+// FreezeAll stops the time of every Clock registered by tb with RegisterFreezable, so that
+// integration tests composed of several such clocks can take a coherent snapshot of their state:
+// while frozen, Now returns the time at which FreezeAll was called, and once ResumeAll is called,
+// time continues from there, as if the pause never happened. Calls nest; each FreezeAll must be
+// matched by a ResumeAll.
+//
+// Timers are paused along with Now: a timer due while its clock is frozen fires after ResumeAll,
+// with as much time left as it had when FreezeAll was called. See NewScaledClock for which timers
+// this applies to.
+func FreezeAll(tb testing.TB) {
+	freezablesMu.Lock()
+	defer freezablesMu.Unlock()
+	for _, f := range freezables[tb] {
+		f.freeze()
+	}
+}
+
+// ResumeAll resumes the clocks frozen by FreezeAll.
+func ResumeAll(tb testing.TB) {
+	freezablesMu.Lock()
+	defer freezablesMu.Unlock()
+	for _, f := range freezables[tb] {
+		f.resume()
+	}
+}
@@ -0,0 +1,39 @@
+package quartz
+
+import "time"
+
+// Frozen calls f with the time observed through the Mock frozen: Now, Since and Until behave as if
+// the time were still the time when Frozen was called, even if the clock is advanced and events
+// fire while f runs. This allows testing logic that must use a single, consistent timestamp, like a
+// report generator that takes a snapshot of the time once per run. Frozen calls may be nested, in
+// which case the outermost one determines the time.
+//
+// Timers and tickers are unaffected, and fire and deliver times as usual.
+func (m *Mock) Frozen(f func()) {
+	m.mu.Lock()
+	if m.frozen == 0 {
+		m.frozenAt = m.cur
+		if !m.testOver {
+			m.logfLocked("Frozen()")
+		}
+	}
+	m.frozen++
+	m.mu.Unlock()
+	defer func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.frozen--
+		if m.frozen == 0 && !m.testOver {
+			m.logfLocked("Frozen() done")
+		}
+	}()
+	f()
+}
+
+// observedNowLocked returns the current time as observed by Now, Since and Until.
+func (m *Mock) observedNowLocked() time.Time {
+	if m.frozen > 0 {
+		return m.frozenAt
+	}
+	return m.cur
+}
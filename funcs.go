@@ -0,0 +1,48 @@
+package quartz
+
+import "time"
+
+// NowFunc returns a function with the signature of time.Now that calls clk.Now with the given
+// tags. It is useful for wiring a Clock into libraries that accept a `nowFunc func() time.Time`,
+// while still allowing the calls to be trapped.
+func NowFunc(clk Clock, tags ...string) func() time.Time {
+	return func() time.Time {
+		return clk.Now(tags...)
+	}
+}
+
+// SinceFunc returns a function with the signature of time.Since that calls clk.Since with the
+// given tags.
+func SinceFunc(clk Clock, tags ...string) func(time.Time) time.Duration {
+	return func(t time.Time) time.Duration {
+		return clk.Since(t, tags...)
+	}
+}
+
+// UntilFunc returns a function with the signature of time.Until that calls clk.Until with the
+// given tags.
+func UntilFunc(clk Clock, tags ...string) func(time.Time) time.Duration {
+	return func(t time.Time) time.Duration {
+		return clk.Until(t, tags...)
+	}
+}
+
+// AfterFn returns a function with the signature of time.After that creates a timer via
+// clk.NewTimer with the given tags and returns its channel. It is useful for libraries that accept
+// an `afterFunc func(time.Duration) <-chan time.Time`.
+func AfterFn(clk Clock, tags ...string) func(time.Duration) <-chan time.Time {
+	return func(d time.Duration) <-chan time.Time {
+		return clk.NewTimer(d, tags...).C
+	}
+}
+
+// AfterFuncFn returns a function that schedules f via clk.AfterFunc with the given tags, and
+// returns a function that cancels it, with the same semantics as Timer.Stop.
+func AfterFuncFn(clk Clock, tags ...string) func(time.Duration, func()) func() bool {
+	return func(d time.Duration, f func()) func() bool {
+		t := clk.AfterFunc(d, f, tags...)
+		return func() bool {
+			return t.Stop(tags...)
+		}
+	}
+}
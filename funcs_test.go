@@ -0,0 +1,61 @@
+package quartz_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/coder/quartz"
+)
+
+func TestNowFunc_Trapped(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mClock := quartz.NewMock(t)
+	trap := mClock.Trap().Now("legacy")
+	defer trap.Close()
+
+	nowFunc := quartz.NowFunc(mClock, "legacy")
+	start := mClock.Now()
+	got := make(chan time.Time, 1)
+	go func() {
+		got <- nowFunc()
+	}()
+	c := trap.MustWait(ctx)
+	mClock.Advance(time.Second).MustWait(ctx)
+	c.MustRelease(ctx)
+	if tme := <-got; !tme.Equal(start.Add(time.Second)) {
+		t.Fatalf("expected %s got %s", start.Add(time.Second), tme)
+	}
+}
+
+func TestAfterFn(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mClock := quartz.NewMock(t)
+	after := quartz.AfterFn(mClock, "legacy")
+	ch := after(time.Second)
+	mClock.Advance(time.Second).MustWait(ctx)
+	select {
+	case <-ch:
+		// OK
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for AfterFn channel")
+	}
+
+	fired := false
+	stop := quartz.AfterFuncFn(mClock, "legacy")(time.Second, func() { fired = true })
+	if !stop() {
+		t.Fatal("expected stop to return true")
+	}
+	if _, ok := mClock.Peek(); ok {
+		t.Fatal("expected no pending events")
+	}
+	if fired {
+		t.Fatal("expected func not to fire")
+	}
+}
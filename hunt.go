@@ -0,0 +1,29 @@
+package quartz
+
+import (
+	"fmt"
+	"testing"
+)
+
+// Hunt runs body n times as subtests, each with a fresh Mock seeded with a different seed, to hunt
+// for flaky failures that depend on the Mock's randomized testing modes, such as WithPerturbation.
+// It stops at the first failing run and reports its seed.
+//
+// The failing run can be reproduced by setting the QUARTZ_SEED environment variable to the
+// reported seed, in which case Hunt runs body only once, with that seed.
+func Hunt(t *testing.T, n int, body func(t testing.TB, m *Mock)) {
+	t.Helper()
+	seed, source := seedFromEnv(t)
+	if source == SeedEnv {
+		n = 1
+	}
+	for i := 0; i < n; i++ {
+		s := seed + int64(i)
+		ok := t.Run(fmt.Sprintf("seed=%d", s), func(t *testing.T) {
+			body(t, NewMock(t).WithSeed(s))
+		})
+		if !ok {
+			t.Fatalf("failed on run %d of %d; reproduce with %s=%d", i+1, n, SeedEnv, s)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package quartz_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/coder/quartz"
+)
+
+func TestHunt(t *testing.T) {
+	t.Setenv(quartz.SeedEnv, "")
+	runs := 0
+	quartz.Hunt(t, 5, func(t testing.TB, m *quartz.Mock) {
+		runs++
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		m.WithLogger(quartz.NoOpLogger).WithPerturbation(0.5)
+		m.NewTimer(time.Second, "a")
+		d, w := m.AdvanceNext()
+		w.MustWait(ctx)
+		if d < time.Second/2 || d > 3*time.Second/2 {
+			t.Fatalf("perturbed duration %s out of range", d)
+		}
+	})
+	if runs != 5 {
+		t.Fatalf("expected 5 runs, got %d", runs)
+	}
+}
+
+func TestHunt_SeedEnv(t *testing.T) {
+	t.Setenv(quartz.SeedEnv, "7")
+	runs := 0
+	quartz.Hunt(t, 100, func(t testing.TB, m *quartz.Mock) {
+		runs++
+	})
+	if runs != 1 {
+		t.Fatalf("expected 1 run with %s set, got %d", quartz.SeedEnv, runs)
+	}
+}
@@ -0,0 +1,83 @@
+package quartz
+
+import (
+	"encoding/binary"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// ID is a ULID-like identifier: a 48-bit big-endian Unix timestamp in milliseconds, followed by 80
+// bits of entropy. IDs sort in the order they were generated by an IDGenerator.
+type ID [16]byte
+
+// crockford is the Crockford base32 alphabet used by ULIDs.
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// String encodes the ID as 26 characters of Crockford base32, like a ULID.
+func (id ID) String() string {
+	var b [26]byte
+	// 128 bits are encoded as 130 bits, with the first character holding the top 3 bits.
+	hi := binary.BigEndian.Uint64(id[:8])
+	lo := binary.BigEndian.Uint64(id[8:])
+	for i := 25; i >= 0; i-- {
+		b[i] = crockford[lo&31]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(b[:])
+}
+
+// Time returns the timestamp of the ID, to millisecond precision.
+func (id ID) Time() time.Time {
+	var ms [8]byte
+	copy(ms[2:], id[:6])
+	return time.UnixMilli(int64(binary.BigEndian.Uint64(ms[:])))
+}
+
+// IDGenerator generates monotonically increasing IDs, timestamped with the time of a Clock. Under a
+// Mock, and with a fixed seed, it generates exactly the same IDs in every run, so systems that
+// embed timestamps in identifiers can be tested byte for byte.
+//
+// IDs generated in the same millisecond, or while the clock goes backwards, keep the timestamp of
+// the previous ID and increment its entropy, so they still sort in the order they were generated.
+type IDGenerator struct {
+	clk  Clock
+	tags []string
+
+	mu   sync.Mutex
+	rand *rand.Rand
+	last ID
+}
+
+// NewIDGenerator returns an IDGenerator that timestamps IDs with clk, and draws their entropy from
+// a random source seeded with seed. The tags are passed to clk.Now, for trapping.
+func NewIDGenerator(clk Clock, seed int64, tags ...string) *IDGenerator {
+	return &IDGenerator{clk: clk, tags: tags, rand: rand.New(rand.NewPCG(uint64(seed), 0))}
+}
+
+// Next returns the next ID.
+func (g *IDGenerator) Next() ID {
+	ms := uint64(g.clk.Now(g.tags...).UnixMilli()) & (1<<48 - 1)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	var ts [8]byte
+	copy(ts[2:], g.last[:6])
+	if last := binary.BigEndian.Uint64(ts[:]); ms <= last && g.last != (ID{}) {
+		// increment the 80 bits of entropy, carrying into the timestamp on overflow.
+		for i := 15; i >= 0; i-- {
+			g.last[i]++
+			if g.last[i] != 0 {
+				break
+			}
+		}
+		return g.last
+	}
+	var id ID
+	binary.BigEndian.PutUint64(ts[:], ms)
+	copy(id[:6], ts[2:])
+	binary.BigEndian.PutUint16(id[6:8], uint16(g.rand.Uint32()))
+	binary.BigEndian.PutUint64(id[8:], g.rand.Uint64())
+	g.last = id
+	return id
+}
@@ -0,0 +1,40 @@
+package quartz_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/coder/quartz"
+)
+
+func TestIDGenerator(t *testing.T) {
+	t.Parallel()
+
+	generate := func() []quartz.ID {
+		mClock := quartz.NewMock(t)
+		gen := quartz.NewIDGenerator(mClock, 42)
+		ids := []quartz.ID{gen.Next(), gen.Next()}
+		mClock.Advance(time.Millisecond)
+		return append(ids, gen.Next())
+	}
+	ids := generate()
+	again := generate()
+	for i := range ids {
+		if ids[i] != again[i] {
+			t.Fatalf("expected ID %d to be deterministic, got %s and %s", i, ids[i], again[i])
+		}
+		if i > 0 && ids[i].String() <= ids[i-1].String() {
+			t.Fatalf("expected IDs to increase, got %s after %s", ids[i], ids[i-1])
+		}
+	}
+	start := quartz.NewMock(t).Now()
+	if got := ids[0].Time(); !got.Equal(start) {
+		t.Fatalf("expected timestamp %s, got %s", start, got)
+	}
+	if got := ids[2].Time(); !got.Equal(start.Add(time.Millisecond)) {
+		t.Fatalf("expected timestamp %s, got %s", start.Add(time.Millisecond), got)
+	}
+	if s := ids[0].String(); len(s) != 26 || s[:10] != "01HK153X00" {
+		t.Fatalf("unexpected encoding %s", s)
+	}
+}
@@ -4,8 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
+	"math"
+	"math/rand/v2"
+	"os"
 	"slices"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -18,6 +23,12 @@ type Mock struct {
 	mu       sync.Mutex
 	testOver bool
 
+	// customLogger is true if logger was set by WithLogger, rather than being tb, or some messages
+	// go to loggers set by WithLoggerFor.
+	customLogger bool
+	// tagLoggers are the loggers for tagged operations, see WithLoggerFor.
+	tagLoggers []tagLogger
+
 	// cur is the current time
 	cur time.Time
 
@@ -25,33 +36,216 @@ type Mock struct {
 	nextTime   time.Time
 	nextEvents []event
 	traps      []*Trap
+
+	// seed and rand drive the randomized testing modes, like perturbation.
+	seed         int64
+	seedSet      bool
+	rand         *rand.Rand
+	perturbation float64
+
+	// autoIncrement is the amount Now advances the time by, if nonzero.
+	autoIncrement time.Duration
+
+	// monoAnchor is a time with a monotonic reading used to add monotonic readings to the times
+	// returned by Now, or zero if disabled.
+	monoAnchor time.Time
+
+	// dropPolicy is applied to dropped ticks, which are counted in droppedTicks.
+	dropPolicy   DropPolicy
+	droppedTicks int
+
+	// maxDuration is the largest duration allowed to be scheduled, if nonzero.
+	maxDuration time.Duration
+
+	// origin is the time the Mock started simulating from, and realStart the real time it was
+	// created, used to report how much virtual time was simulated.
+	origin        time.Time
+	realStart     time.Time
+	reportingTime bool
+
+	// contextTagKeys are the context keys whose values are appended to tags, see WithContextTags.
+	contextTagKeys []any
+
+	// lastLog is the time of the previous log message.
+	lastLog time.Time
+	// history holds the most recent log messages, to dump if the test fails.
+	history timeline
+
+	// testCtx is the context of the test, if the TB provides one.
+	testCtx context.Context
+
+	// pool runs events, if set by WithWorkerPool.
+	pool *workerPool
+
+	// strictTraps fails the test if a trap is closed without matching a call.
+	strictTraps bool
+	// strictTags fails the test if a call or trap uses an unregistered tag, see WithStrictTags.
+	strictTags bool
+	// tagMapper rewrites the tags of calls, see WithTagMapper.
+	tagMapper func([]string) []string
+
+	// expectations are the calls declared with Expect.
+	expectations []*Expectation
+
+	// waitProgress is the interval at which waiting AdvanceWaiters log progress, see
+	// WithWaitProgress.
+	waitProgress time.Duration
+
+	// syncTicks are the sets of tags of tickers that deliver ticks synchronously.
+	syncTicks [][]string
+
+	// advancing are the AdvanceWaiters of advances in progress.
+	advancing []AdvanceWaiter
+
+	// failOnIdleAdvance fails the test if Advance is called with no events scheduled.
+	failOnIdleAdvance bool
+
+	// afterFuncExec, if set, runs functions passed to AfterFunc.
+	afterFuncExec func(fn func())
+
+	// scheduled are the events that have been scheduled, in order, see Scheduled. At most
+	// scheduledLimit are kept, or defaultScheduledLimit if it is zero, and scheduledDropped counts
+	// those dropped beyond the limit.
+	scheduled        []EventInfo
+	scheduledLimit   int
+	scheduledDropped int
+	// timerCounts count the timers created by the code under test by their tags, see TimerStats.
+	timerCounts map[string]*timerCounts
+	// tickerEvents are the operations on Tickers, see TickerEvents.
+	tickerEvents []TickerEvent
+
+	// eventAdded, if not nil, is closed when the next event is scheduled, see WaitForEvents.
+	eventAdded chan struct{}
+	// quiesce, if set, is called before the methods advancing the clock return, see WithSynctest.
+	quiesce func()
+	// beforeAdvance are called at the start of the methods advancing the clock, see BeforeAdvance.
+	beforeAdvance []func(now time.Time)
+	// calledTags counts the calls made with each tag, and callMade, if not nil, is closed when the
+	// next call is made, see WaitForCall.
+	calledTags map[string]int
+	callMade   chan struct{}
+	// callRecorders collect descriptions of the calls made, see AssertNoClockCalls.
+	callRecorders []*[]string
+
+	// monotonicity, if set, checks that observed times don't go backwards.
+	monotonicity *monotonicityCheck
+
+	// activity counts calls to the Mock and scheduled events, see AutoAdvance.
+	activity int
+
+	// frozen is the depth of nested Frozen calls, and frozenAt the time they froze.
+	frozen   int
+	frozenAt time.Time
 }
 
 type event interface {
 	next() time.Time
 	fire(t time.Time)
+	info() EventInfo
+}
+
+// EventInfo describes a timer, ticker or other event scheduled on a Mock.
+type EventInfo struct {
+	// Kind is the name of the Clock method that created the event, e.g. "NewTimer" or
+	// "TickerFunc".
+	Kind string
+	// Tags are the tags the event was created with.
+	Tags []string
+	// Deadline is the time the event is, or was, scheduled to fire.
+	Deadline time.Time
+	// Duration is the duration the event was scheduled with, or for tickers, their period.
+	Duration time.Duration
+}
+
+func (e EventInfo) String() string {
+	return fmt.Sprintf("%s(%s, %v) at %s", e.Kind, e.Duration, e.Tags, e.Deadline)
 }
 
-func (m *Mock) TickerFunc(ctx context.Context, d time.Duration, f func() error, tags ...string) Waiter {
+func (m *Mock) TickerFunc(ctx context.Context, d time.Duration, f func() error, tags ...string) StopWaiter {
 	if d <= 0 {
 		panic("TickerFunc called with negative or zero duration")
 	}
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	c := newCall(clockFunctionTickerFunc, tags, withDuration(d))
+	c := newCall(clockFunctionTickerFunc, m.contextTagsLocked(ctx, tags), withDuration(d))
+	ctx, release := m.withTestContext(ctx)
 	m.matchCallLocked(c)
 	defer close(c.complete)
+	d = m.scheduleDurationLocked(c)
 	t := &mockTickerFunc{
-		ctx:  ctx,
-		d:    d,
-		f:    f,
-		nxt:  m.cur.Add(d),
-		mock: m,
-		cond: sync.NewCond(&m.mu),
+		ctx:     ctx,
+		release: release,
+		d:       d,
+		tags:    c.Tags,
+		f:       f,
+		nxt:     addDuration(m.cur, d),
+		mock:    m,
+		cond:    sync.NewCond(&m.mu),
+		exited:  make(chan struct{}),
+	}
+	m.addEventLocked(t)
+	go t.waitForCtx()
+	return t
+}
+
+func (m *Mock) AdaptiveTickerFunc(
+	ctx context.Context, interval func(n int, last error) time.Duration, f func() error, tags ...string,
+) StopWaiter {
+	d := interval(0, nil)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	c := newCall(clockFunctionAdaptiveTickerFunc, m.contextTagsLocked(ctx, tags), withDuration(d))
+	ctx, release := m.withTestContext(ctx)
+	m.matchCallLocked(c)
+	defer close(c.complete)
+	t := &mockTickerFunc{
+		ctx:      ctx,
+		release:  release,
+		d:        d,
+		tags:     c.Tags,
+		f:        f,
+		interval: interval,
+		mock:     m,
+		cond:     sync.NewCond(&m.mu),
+		exited:   make(chan struct{}),
+	}
+	go t.waitForCtx()
+	if d <= 0 {
+		t.exitLocked(nil)
+		return t
+	}
+	t.nxt = addDuration(m.cur, m.scheduleDurationLocked(c))
+	m.addEventLocked(t)
+	return t
+}
+
+func (m *Mock) TimerFunc(ctx context.Context, d time.Duration, f func() error, tags ...string) Waiter {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	c := newCall(clockFunctionTimerFunc, m.contextTagsLocked(ctx, tags), withDuration(d))
+	ctx, release := m.withTestContext(ctx)
+	m.matchCallLocked(c)
+	defer close(c.complete)
+	t := &mockTimerFunc{
+		ctx:     ctx,
+		release: release,
+		d:       d,
+		tags:    c.Tags,
+		f:       f,
+		nxt:     addDuration(m.cur, m.scheduleDurationLocked(c)),
+		mock:    m,
+		cond:    sync.NewCond(&m.mu),
+		exited:  make(chan struct{}),
 	}
-	m.all = append(m.all, t)
-	m.recomputeNextLocked()
 	go t.waitForCtx()
+	if d <= 0 {
+		// zero or negative duration timer means we should immediately fire
+		// it, rather than add it. It is due now, so it delivers the current time.
+		t.nxt = m.cur
+		m.goFireLocked(t, t.nxt)
+		return t
+	}
+	m.addEventLocked(t)
 	return t
 }
 
@@ -67,7 +261,12 @@ func (m *Mock) NewTicker(d time.Duration, tags ...string) *Ticker {
 	c := newCall(clockFunctionNewTicker, tags, withDuration(d))
 	m.matchCallLocked(c)
 	defer close(c.complete)
-	return newMockTickerLocked(m, d)
+	t := newMockTickerLocked(m, m.scheduleDurationLocked(c), c.Tags)
+	t.recordLocked(clockFunctionNewTicker, 0, t.d)
+	if c.canceled {
+		t.stopLocked()
+	}
+	return t
 }
 
 func (m *Mock) NewTimer(d time.Duration, tags ...string) *Timer {
@@ -80,13 +279,138 @@ func (m *Mock) NewTimer(d time.Duration, tags ...string) *Timer {
 	t := &Timer{
 		C:    ch,
 		c:    ch,
-		nxt:  m.cur.Add(d),
+		nxt:  addDuration(m.cur, m.scheduleDurationLocked(c)),
+		mock: m,
+		kind: clockFunctionNewTimer,
+		tags: c.Tags,
+		d:    d,
+	}
+	m.trackTimerLocked(t)
+	if c.canceled {
+		t.stopped = true
+		return t
+	}
+	if d <= 0 {
+		// zero or negative duration timer means we should immediately fire
+		// it, rather than add it. It is due now, so it delivers the current time.
+		t.nxt = m.cur
+		m.goFireLocked(t, t.nxt)
+		return t
+	}
+	m.addEventLocked(t)
+	return t
+}
+
+func (m *Mock) After(d time.Duration, tags ...string) <-chan time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	c := newCall(clockFunctionAfter, tags, withDuration(d))
+	defer close(c.complete)
+	m.matchCallLocked(c)
+	ch := make(chan time.Time)
+	t := &Timer{
+		C:    ch,
+		c:    ch,
+		nxt:  addDuration(m.cur, m.scheduleDurationLocked(c)),
+		mock: m,
+		kind: clockFunctionAfter,
+		tags: c.Tags,
+		d:    d,
+	}
+	m.trackTimerLocked(t)
+	if c.canceled {
+		t.stopped = true
+		return ch
+	}
+	if d <= 0 {
+		// zero or negative duration timer means we should immediately fire
+		// it, rather than add it. It is due now, so it delivers the current time.
+		t.nxt = m.cur
+		m.goFireLocked(t, t.nxt)
+		return ch
+	}
+	m.addEventLocked(t)
+	return ch
+}
+
+// Sleep blocks until the clock is advanced by at least d. The sleep is scheduled as an event, so
+// Advance and AdvanceNext wake it, and the call can be trapped before it starts sleeping.
+// Sleep also returns at the end of the test, so that sleeping goroutines do not leak.
+func (m *Mock) Sleep(d time.Duration, tags ...string) {
+	_ = m.sleep(context.Background(), clockFunctionSleep, d, tags)
+}
+
+// SleepContext is like Sleep, but returns early with the context error if ctx expires first, in
+// which case the sleep is removed from the schedule.
+func (m *Mock) SleepContext(ctx context.Context, d time.Duration, tags ...string) error {
+	return m.sleep(ctx, clockFunctionSleepContext, d, tags)
+}
+
+func (m *Mock) sleep(ctx context.Context, fn clockFunction, d time.Duration, tags []string) error {
+	m.mu.Lock()
+	c := newCall(fn, m.contextTagsLocked(ctx, tags), withDuration(d))
+	m.matchCallLocked(c)
+	if d <= 0 {
+		close(c.complete)
+		m.mu.Unlock()
+		return ctx.Err()
+	}
+	ch := make(chan time.Time)
+	t := &Timer{
+		C:    ch,
+		c:    ch,
+		nxt:  addDuration(m.cur, m.scheduleDurationLocked(c)),
+		mock: m,
+		kind: fn,
+		tags: c.Tags,
+		d:    d,
+	}
+	m.addEventLocked(t)
+	ctx, release := m.withTestContext(ctx)
+	defer release()
+	// the call is complete once the sleep is scheduled, so that releasing a trapped Sleep does not
+	// wait for it to finish.
+	close(c.complete)
+	m.mu.Unlock()
+	select {
+	case <-ch:
+		return nil
+	case <-ctx.Done():
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.removeTimerLocked(t)
+		if t.interrupt != nil {
+			<-t.interrupt
+			t.interrupt = nil
+		}
+		return ctx.Err()
+	}
+}
+
+func (m *Mock) NewTimerInto(ch chan<- time.Time, d time.Duration, tags ...string) *Timer {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	c := newCall(clockFunctionNewTimerInto, tags, withDuration(d))
+	defer close(c.complete)
+	m.matchCallLocked(c)
+	t := &Timer{
+		c:    ch,
+		nxt:  addDuration(m.cur, m.scheduleDurationLocked(c)),
 		mock: m,
+		kind: clockFunctionNewTimerInto,
+		tags: c.Tags,
+		d:    d,
+	}
+	m.trackTimerLocked(t)
+	if c.canceled {
+		t.stopped = true
+		return t
 	}
 	if d <= 0 {
 		// zero or negative duration timer means we should immediately fire
-		// it, rather than add it.
-		go t.fire(t.mock.cur)
+		// it, rather than add it. It is due now, so it delivers the current time.
+		t.nxt = m.cur
+		m.goFireLocked(t, t.nxt)
 		return t
 	}
 	m.addEventLocked(t)
@@ -96,18 +420,27 @@ func (m *Mock) NewTimer(d time.Duration, tags ...string) *Timer {
 func (m *Mock) AfterFunc(d time.Duration, f func(), tags ...string) *Timer {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	c := newCall(clockFunctionAfterFunc, tags, withDuration(d))
+	c := newCall(clockFunctionAfterFunc, tags, withDuration(d), withFunc(f))
 	defer close(c.complete)
 	m.matchCallLocked(c)
 	t := &Timer{
-		nxt:  m.cur.Add(d),
-		fn:   f,
+		nxt:  addDuration(m.cur, m.scheduleDurationLocked(c)),
+		fn:   c.f,
 		mock: m,
+		kind: clockFunctionAfterFunc,
+		tags: c.Tags,
+		d:    d,
+	}
+	m.trackTimerLocked(t)
+	if c.canceled {
+		t.stopped = true
+		return t
 	}
 	if d <= 0 {
 		// zero or negative duration timer means we should immediately fire
-		// it, rather than add it.
-		go t.fire(t.mock.cur)
+		// it, rather than add it. It is due now, so it delivers the current time.
+		t.nxt = m.cur
+		m.goFireLocked(t, t.nxt)
 		return t
 	}
 	m.addEventLocked(t)
@@ -120,7 +453,20 @@ func (m *Mock) Now(tags ...string) time.Time {
 	c := newCall(clockFunctionNow, tags)
 	defer close(c.complete)
 	m.matchCallLocked(c)
-	return m.cur
+	now := m.observedNowLocked()
+	if c.now != nil {
+		// overridden by ReleaseWith; deliberately skewed times are not checked for monotonicity.
+		return *c.now
+	}
+	if m.autoIncrement > 0 && m.frozen == 0 {
+		m.cur = m.cur.Add(m.autoIncrement)
+		// never auto-increment past an event; it must be fired by advancing the clock.
+		if !m.nextTime.IsZero() && m.cur.After(m.nextTime) {
+			m.cur = m.nextTime
+		}
+	}
+	m.observeNowLocked(now)
+	return m.withMonotonicLocked(now)
 }
 
 func (m *Mock) Since(t time.Time, tags ...string) time.Duration {
@@ -129,7 +475,10 @@ func (m *Mock) Since(t time.Time, tags ...string) time.Duration {
 	c := newCall(clockFunctionSince, tags, withTime(t))
 	defer close(c.complete)
 	m.matchCallLocked(c)
-	return m.cur.Sub(t)
+	if c.now != nil {
+		return c.now.Sub(t)
+	}
+	return m.observedNowLocked().Sub(t)
 }
 
 func (m *Mock) Until(t time.Time, tags ...string) time.Duration {
@@ -138,10 +487,19 @@ func (m *Mock) Until(t time.Time, tags ...string) time.Duration {
 	c := newCall(clockFunctionUntil, tags, withTime(t))
 	defer close(c.complete)
 	m.matchCallLocked(c)
-	return t.Sub(m.cur)
+	if c.now != nil {
+		return t.Sub(*c.now)
+	}
+	return t.Sub(m.observedNowLocked())
 }
 
 func (m *Mock) addEventLocked(e event) {
+	m.activity++
+	m.recordScheduledLocked(e)
+	if m.eventAdded != nil {
+		close(m.eventAdded)
+		m.eventAdded = nil
+	}
 	m.all = append(m.all, e)
 	m.recomputeNextLocked()
 }
@@ -186,24 +544,62 @@ func (m *Mock) removeEventLocked(e event) {
 }
 
 func (m *Mock) matchCallLocked(c *apiCall) {
+	if m.tagMapper != nil {
+		c.Tags = m.tagMapper(slices.Clone(c.Tags))
+	}
+	if m.strictTags {
+		// formatting the call is costly, and only needed to report unregistered tags.
+		m.checkTagsLocked(c.String(), c.Tags)
+	}
+	m.recordExpectationsLocked(c)
+	m.activity++
+	m.recordCallTagsLocked(c.Tags)
+	for _, r := range m.callRecorders {
+		*r = append(*r, c.String())
+	}
+	c.virtualTime = m.cur
+	c.realTime = time.Now()
 	var traps []*Trap
 	for _, t := range m.traps {
 		if t.matches(c) {
+			t.matched++
 			traps = append(traps, t)
 		}
 	}
 	if !m.testOver {
-		m.logger.Logf("Mock Clock - %s call, matched %d traps", c, len(traps))
+		m.logfTaggedLocked(c.Tags, "%s call, matched %d traps", c, len(traps))
 	}
 	if len(traps) == 0 {
 		return
 	}
-	c.releases.Add(len(traps))
-	m.mu.Unlock()
 	for _, t := range traps {
-		go t.catch(c)
+		t.scheduleReleaseLocked(c)
+	}
+	// deliver the call to each priority level of traps in turn, highest first. SortStableFunc
+	// preserves the order the traps were created in within a level.
+	slices.SortStableFunc(traps, func(a, b *Trap) int {
+		return b.priority - a.priority
+	})
+	m.mu.Unlock()
+	for len(traps) > 0 {
+		n := 1
+		for n < len(traps) && traps[n].priority == traps[0].priority {
+			n++
+		}
+		c.stageReleased = nil
+		if n < len(traps) {
+			c.stageReleased = make(chan struct{})
+		}
+		c.releases.Add(n)
+		for _, t := range traps[:n] {
+			go t.catch(c, t.enqueue(c))
+		}
+		c.releases.Wait()
+		if c.stageReleased != nil {
+			close(c.stageReleased)
+		}
+		traps = traps[n:]
 	}
-	c.releases.Wait()
 	m.mu.Lock()
 }
 
@@ -215,18 +611,111 @@ func (m *Mock) matchCallLocked(c *apiCall) {
 // If multiple timers or tickers trigger simultaneously, they are all run on separate
 // go routines.
 type AdvanceWaiter struct {
-	tb testing.TB
-	ch chan struct{}
+	tb     testing.TB
+	mock   *Mock
+	ch     chan struct{}
+	events *advanceEvents
 }
 
-// Wait for all timers and ticks to complete, or until context expires.
-func (w AdvanceWaiter) Wait(ctx context.Context) error {
-	select {
-	case <-w.ch:
-		return nil
-	case <-ctx.Done():
-		return ctx.Err()
+func newAdvanceWaiter(m *Mock) AdvanceWaiter {
+	return AdvanceWaiter{tb: m.tb, mock: m, ch: make(chan struct{}), events: &advanceEvents{}}
+}
+
+// advanceEvents tracks the events triggered by an advance, and whether they have completed.
+type advanceEvents struct {
+	mu      sync.Mutex
+	fired   []EventInfo
+	firedAt []time.Time
+	done    []bool
+
+	// target is the time being advanced to, protected by the Mock's mutex.
+	target time.Time
+}
+
+func (a *advanceEvents) add(e EventInfo, at time.Time) int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.fired = append(a.fired, e)
+	a.firedAt = append(a.firedAt, at)
+	a.done = append(a.done, false)
+	return len(a.fired) - 1
+}
+
+func (a *advanceEvents) complete(i int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.done[i] = true
+}
+
+// Events returns the timer and tick events triggered by the advance. Once the AdvanceWaiter is
+// done, this is every event it waited on.
+func (w AdvanceWaiter) Events() []EventInfo {
+	w.events.mu.Lock()
+	defer w.events.mu.Unlock()
+	return slices.Clone(w.events.fired)
+}
+
+// FiredEvent is an event fired by an advance, see AdvanceWaiter.Fired.
+type FiredEvent struct {
+	EventInfo
+	// FiredAt is the time on the Mock at which the event fired. It is the event's Deadline, unless
+	// the event fired late, e.g. because of Jump.
+	FiredAt time.Time
+}
+
+func (e FiredEvent) String() string {
+	return fmt.Sprintf("%s fired at %s", e.EventInfo, e.FiredAt)
+}
+
+// Fired returns the events fired by the advance, in the order they fired, with the time on the Mock
+// at which each fired. Like Events, once the AdvanceWaiter is done, this is every event it waited
+// on, which allows tests to verify which timers went off during the advance.
+func (w AdvanceWaiter) Fired() []FiredEvent {
+	w.events.mu.Lock()
+	defer w.events.mu.Unlock()
+	fired := make([]FiredEvent, len(w.events.fired))
+	for i, e := range w.events.fired {
+		fired[i] = FiredEvent{EventInfo: e, FiredAt: w.events.firedAt[i]}
+	}
+	return fired
+}
+
+// Pending returns the timer and tick events triggered by the advance that have not yet completed,
+// e.g. because the function passed to AfterFunc or TickerFunc is still running.
+func (w AdvanceWaiter) Pending() []EventInfo {
+	w.events.mu.Lock()
+	defer w.events.mu.Unlock()
+	var pending []EventInfo
+	for i, e := range w.events.fired {
+		if !w.events.done[i] {
+			pending = append(pending, e)
+		}
+	}
+	return pending
+}
+
+// String summarizes the AdvanceWaiter: whether it is done, and the events it is waiting on.
+func (w AdvanceWaiter) String() string {
+	state := "waiting"
+	if w.done() {
+		state = "done"
 	}
+	return fmt.Sprintf("AdvanceWaiter(%s, %d events, pending %v)", state, len(w.Events()), w.Pending())
+}
+
+// LogValue implements slog.LogValuer.
+func (w AdvanceWaiter) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Bool("done", w.done()),
+		slog.Int("events", len(w.Events())),
+		slog.Any("pending", w.Pending()),
+	)
+}
+
+// Wait for all timers and ticks to complete, or until context expires. See Mock.WithWaitProgress to
+// log the events still running while it waits.
+func (w AdvanceWaiter) Wait(ctx context.Context) error {
+	return w.wait(ctx)
 }
 
 // MustWait waits for all timers and ticks to complete, and fails the test immediately if the
@@ -234,10 +723,11 @@ func (w AdvanceWaiter) Wait(ctx context.Context) error {
 // benchmark, similar to `t.FailNow()`.
 func (w AdvanceWaiter) MustWait(ctx context.Context) {
 	w.tb.Helper()
-	select {
-	case <-w.ch:
-		return
-	case <-ctx.Done():
+	if w.wait(ctx) != nil {
+		if pending := w.Pending(); len(pending) > 0 {
+			w.tb.Fatalf("context expired while waiting for clock to advance: %s; still waiting on %v",
+				ctx.Err(), pending)
+		}
 		w.tb.Fatalf("context expired while waiting for clock to advance: %s", ctx.Err())
 	}
 }
@@ -247,70 +737,252 @@ func (w AdvanceWaiter) Done() <-chan struct{} {
 	return w.ch
 }
 
+func (w AdvanceWaiter) done() bool {
+	select {
+	case <-w.ch:
+		return true
+	default:
+		return false
+	}
+}
+
 // Advance moves the clock forward by d, triggering any timers or tickers.  The returned value can
 // be used to wait for all timers and ticks to complete.  Advance sets the clock forward before
 // returning, and can only advance up to the next timer or tick event. It will fail the test if you
 // attempt to advance beyond.
 //
+// Events scheduled for the same instant are dispatched concurrently, each on its own goroutine, so
+// tests exercise the same races between sibling timers that production code would experience. Use
+// WithWorkerPool to limit the concurrency.
+//
 // If you need to advance exactly to the next event, and don't know or don't wish to calculate it,
 // consider AdvanceNext().
 func (m *Mock) Advance(d time.Duration) AdvanceWaiter {
 	m.tb.Helper()
-	w := AdvanceWaiter{tb: m.tb, ch: make(chan struct{})}
+	defer m.settle()
+	m.runBeforeAdvance()
+	w := newAdvanceWaiter(m)
 	m.mu.Lock()
 	if !m.testOver {
-		m.logger.Logf("Mock Clock - Advance(%s)", d)
+		m.logfLocked("Advance(%s)", d)
 	}
-	fin := m.cur.Add(d)
+	c := newCall(clockFunctionAdvance, nil, withDuration(d))
+	defer close(c.complete)
+	m.matchHarnessCallLocked(c)
+	m.checkIdleAdvanceLocked("Advance")
+	m.advanceByLocked(w, d)
+	return w
+}
+
+// advanceByLocked moves the clock forward by d, as for Advance, and unlocks the Mock.
+func (m *Mock) advanceByLocked(w AdvanceWaiter, d time.Duration) {
+	fin := addDuration(m.cur, d)
 	// nextTime.IsZero implies no events scheduled.
 	if m.nextTime.IsZero() || fin.Before(m.nextTime) {
 		m.cur = fin
 		m.mu.Unlock()
 		close(w.ch)
-		return w
+		return
 	}
 	if fin.After(m.nextTime) {
 		m.tb.Errorf("cannot advance %s which is beyond next timer/ticker event in %s",
 			d.String(), m.nextTime.Sub(m.cur))
 		m.mu.Unlock()
 		close(w.ch)
-		return w
+		return
 	}
 
 	m.cur = m.nextTime
 	go m.advanceLocked(w)
-	return w
 }
 
 func (m *Mock) advanceLocked(w AdvanceWaiter) {
 	defer close(w.ch)
+	m.startAdvanceLocked(w, m.cur)
+	m.fireEventsLocked(w)
+	m.mu.Lock()
+	m.finishAdvanceLocked(w)
+	m.mu.Unlock()
+}
+
+// startAdvanceLocked records that an advance to target is in progress, for AdvanceStatus.
+func (m *Mock) startAdvanceLocked(w AdvanceWaiter, target time.Time) {
+	w.events.target = target
+	m.advancing = append(m.advancing, w)
+}
+
+// finishAdvanceLocked records that an advance is no longer in progress.
+func (m *Mock) finishAdvanceLocked(w AdvanceWaiter) {
+	m.advancing = slices.DeleteFunc(m.advancing, func(a AdvanceWaiter) bool {
+		return a.events == w.events
+	})
+}
+
+// AdvanceStatus describes the advances of a Mock that are in progress.
+type AdvanceStatus struct {
+	// Advancing is true if an advance is in progress, i.e. an AdvanceWaiter is not yet done.
+	Advancing bool
+	// Now is the current time of the Mock.
+	Now time.Time
+	// Target is the time the latest advance in progress is advancing to.
+	Target time.Time
+	// Remaining is the number of events scheduled at or before Target that have not yet fired.
+	Remaining int
+	// InFlight are the events that have fired, but have not completed, e.g. because the function
+	// passed to AfterFunc or TickerFunc is still running.
+	InFlight []EventInfo
+}
+
+// Advancing returns whether an advance of the Mock is in progress, i.e. the AdvanceWaiter returned
+// by Advance, AdvanceNext, AdvanceBatch or Set is not yet done.
+func (m *Mock) Advancing() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.advancing) > 0
+}
+
+// AdvanceStatus returns the status of the advances of the Mock that are in progress. It allows
+// test utilities and watchdogs to distinguish an advance stuck in a callback from a test that
+// never advanced the clock.
+func (m *Mock) AdvanceStatus() AdvanceStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	st := AdvanceStatus{Advancing: len(m.advancing) > 0, Now: m.cur, Target: m.cur}
+	for _, w := range m.advancing {
+		st.InFlight = append(st.InFlight, w.Pending()...)
+		if w.events.target.After(st.Target) {
+			st.Target = w.events.target
+		}
+	}
+	for _, e := range m.all {
+		if !e.next().After(st.Target) {
+			st.Remaining++
+		}
+	}
+	return st
+}
+
+// fireEventsLocked fires the events scheduled at the current time, recording them on the waiter,
+// then releases the lock and waits for them to complete.
+func (m *Mock) fireEventsLocked(w AdvanceWaiter) {
 	wg := sync.WaitGroup{}
+	fires := make([]func(), 0, len(m.nextEvents))
 	for i := range m.nextEvents {
 		e := m.nextEvents[i]
 		t := m.cur
+		idx := w.events.add(e.info(), t)
 		wg.Add(1)
-		go func() {
+		fires = append(fires, func() {
 			e.fire(t)
+			w.events.complete(idx)
 			wg.Done()
-		}()
+		})
+	}
+	pool := m.pool
+	if pool == nil {
+		for _, f := range fires {
+			go f()
+		}
 	}
 	// release the lock and let the events resolve.  This allows them to call back into the
 	// Mock to query the time or set new timers.  Each event should remove or reschedule
 	// itself from nextEvents.
 	m.mu.Unlock()
+	if pool != nil {
+		// submitting to the pool blocks until a worker is free, so we must not hold the lock.
+		for _, f := range fires {
+			pool.run(f)
+		}
+	}
 	wg.Wait()
 }
 
-// Set the time to t.  If the time is after the current mocked time, then this is equivalent to
-// Advance() with the difference.  You may only Set the time earlier than the current time before
-// starting tickers and timers (e.g. at the start of your test case).
+// AdvanceBatch moves the clock forward by each of the durations in turn, as if Advance(d) were
+// called and waited on for each one, but in a single pass with a single AdvanceWaiter. Consecutive
+// advances that don't reach any timer or tick event are coalesced, which makes it much cheaper
+// than looping over Advance when simulating virtual time in many small steps.
+//
+// Like Advance, each individual step may only advance up to the next timer or tick event. The
+// clock is advanced synchronously up to the first event; subsequent steps are processed after
+// the events complete, and the returned AdvanceWaiter completes once all steps are processed.
+func (m *Mock) AdvanceBatch(ds ...time.Duration) AdvanceWaiter {
+	m.tb.Helper()
+	defer m.settle()
+	m.runBeforeAdvance()
+	w := newAdvanceWaiter(m)
+	m.mu.Lock()
+	if !m.testOver {
+		m.logfLocked("AdvanceBatch(%d advances)", len(ds))
+	}
+	m.checkIdleAdvanceLocked("AdvanceBatch")
+	rest, fire := m.advanceBatchStepsLocked(ds)
+	if !fire {
+		m.mu.Unlock()
+		close(w.ch)
+		return w
+	}
+	target := m.cur
+	for _, d := range rest {
+		target = addDuration(target, d)
+	}
+	go func() {
+		defer close(w.ch)
+		m.startAdvanceLocked(w, target)
+		for fire {
+			m.fireEventsLocked(w)
+			m.mu.Lock()
+			rest, fire = m.advanceBatchStepsLocked(rest)
+		}
+		m.finishAdvanceLocked(w)
+		m.mu.Unlock()
+	}()
+	return w
+}
+
+// advanceBatchStepsLocked advances the clock through the durations until one of them reaches the
+// next event. It returns the remaining durations, and whether there are events to fire at the
+// current time.
+func (m *Mock) advanceBatchStepsLocked(ds []time.Duration) ([]time.Duration, bool) {
+	m.tb.Helper()
+	for i, d := range ds {
+		fin := addDuration(m.cur, d)
+		// nextTime.IsZero implies no events scheduled.
+		if m.nextTime.IsZero() || fin.Before(m.nextTime) {
+			m.cur = fin
+			continue
+		}
+		if fin.After(m.nextTime) {
+			m.tb.Errorf("cannot advance %s which is beyond next timer/ticker event in %s",
+				d.String(), m.nextTime.Sub(m.cur))
+			return nil, false
+		}
+		m.cur = m.nextTime
+		return ds[i+1:], true
+	}
+	return nil, false
+}
+
+// Set the time to t.  If the time is after the current mocked time, the clock is moved forward,
+// firing each timer and tick event scheduled up to t at its scheduled time, in order, as if
+// AdvanceNext were called and waited on until reaching t, and then Advance with the remainder.
+// This makes it easy to move to specific wall clock instants, like midnight, without computing
+// durations by hand. Unlike AdvanceTo, Set can also move the clock backwards. To move to t in a
+// single step, without firing events at intermediate times, use Jump.
+//
+// You may only Set the time earlier than the current time before starting tickers and timers (e.g.
+// at the start of your test case).
 func (m *Mock) Set(t time.Time) AdvanceWaiter {
 	m.tb.Helper()
-	w := AdvanceWaiter{tb: m.tb, ch: make(chan struct{})}
+	defer m.settle()
+	m.runBeforeAdvance()
+	w := newAdvanceWaiter(m)
 	m.mu.Lock()
 	if !m.testOver {
-		m.logger.Logf("Mock Clock - Set(%s)", t)
+		m.logfLocked("Set(%s)", t)
 	}
+	c := newCall(clockFunctionSet, nil, withTime(t))
+	defer close(c.complete)
+	m.matchHarnessCallLocked(c)
 	if t.Before(m.cur) {
 		defer close(w.ch)
 		defer m.mu.Unlock()
@@ -318,27 +990,110 @@ func (m *Mock) Set(t time.Time) AdvanceWaiter {
 		if !m.nextTime.IsZero() {
 			m.tb.Error("Set mock clock to the past after timers/tickers started")
 		}
+		// moving to the past doesn't count towards the time simulated
+		m.origin = m.origin.Add(t.Sub(m.cur))
 		m.cur = t
+		m.resetMonotonicityLocked()
 		return w
 	}
 	// future
+	m.advanceToLocked(w, t)
+	return w
+}
+
+// AdvanceTo moves the clock forward to target, firing each timer and tick event scheduled up to
+// target at its scheduled time, in order, so that e.g. each tick of a ticker is delivered with the
+// correct timestamp. The returned AdvanceWaiter completes once all the events, including any they
+// schedule up to target, have completed. It fails the test if target is before the current time.
+func (m *Mock) AdvanceTo(target time.Time) AdvanceWaiter {
+	m.tb.Helper()
+	defer m.settle()
+	m.runBeforeAdvance()
+	w := newAdvanceWaiter(m)
+	m.mu.Lock()
+	if !m.testOver {
+		m.logfLocked("AdvanceTo(%s)", target)
+	}
+	if target.Before(m.cur) {
+		defer close(w.ch)
+		defer m.mu.Unlock()
+		m.tb.Errorf("cannot AdvanceTo %s which is before the current time %s", target, m.cur)
+		return w
+	}
+	m.checkIdleAdvanceLocked("AdvanceTo")
+	m.advanceToLocked(w, target)
+	return w
+}
+
+// advanceToLocked advances the clock to t, firing the events on the way, and unlocks the Mock.
+func (m *Mock) advanceToLocked(w AdvanceWaiter, t time.Time) {
+	if !m.setStepLocked(t) {
+		m.mu.Unlock()
+		close(w.ch)
+		return
+	}
+	go func() {
+		defer close(w.ch)
+		m.startAdvanceLocked(w, t)
+		for fire := true; fire; fire = m.setStepLocked(t) {
+			m.fireEventsLocked(w)
+			m.mu.Lock()
+		}
+		m.finishAdvanceLocked(w)
+		m.mu.Unlock()
+	}()
+}
+
+// setStepLocked advances the clock towards t, stopping at the next event, if it is no later than t.
+// It returns whether there are events to fire at the current time.
+func (m *Mock) setStepLocked(t time.Time) bool {
 	// nextTime.IsZero implies no events scheduled.
 	if m.nextTime.IsZero() || t.Before(m.nextTime) {
+		m.cur = t
+		return false
+	}
+	m.cur = m.nextTime
+	return true
+}
+
+// Jump moves the clock forward to t in a single step, as if the process were suspended, e.g. by the
+// machine sleeping, until t. Timer and tick events scheduled up to t do not fire at their scheduled
+// times: each fires once, late, at t, and tickers skip the ticks in between. This is much cheaper
+// than Set when jumping far ahead of frequent tickers. It fails the test if t is before the
+// current time.
+func (m *Mock) Jump(t time.Time) AdvanceWaiter {
+	m.tb.Helper()
+	defer m.settle()
+	m.runBeforeAdvance()
+	w := newAdvanceWaiter(m)
+	m.mu.Lock()
+	if !m.testOver {
+		m.logfLocked("Jump(%s)", t)
+	}
+	if t.Before(m.cur) {
 		defer close(w.ch)
 		defer m.mu.Unlock()
-		m.cur = t
+		m.tb.Errorf("cannot Jump to %s which is before the current time %s", t, m.cur)
 		return w
 	}
-	if t.After(m.nextTime) {
+	m.checkIdleAdvanceLocked("Jump")
+	m.cur = t
+	// nextTime.IsZero implies no events scheduled.
+	if m.nextTime.IsZero() || m.nextTime.After(t) {
 		defer close(w.ch)
 		defer m.mu.Unlock()
-		m.tb.Errorf("cannot Set time to %s which is beyond next timer/ticker event at %s",
-			t.String(), m.nextTime)
 		return w
 	}
-
-	m.cur = m.nextTime
-	go m.advanceLocked(w)
+	go func() {
+		defer close(w.ch)
+		m.startAdvanceLocked(w, t)
+		for !m.nextTime.IsZero() && !m.nextTime.After(t) {
+			m.fireEventsLocked(w)
+			m.mu.Lock()
+		}
+		m.finishAdvanceLocked(w)
+		m.mu.Unlock()
+	}()
 	return w
 }
 
@@ -346,22 +1101,258 @@ func (m *Mock) Set(t time.Time) AdvanceWaiter {
 // none scheduled.  It returns the duration the clock was advanced and a waiter that can be used to
 // wait for the timer/tick event(s) to finish.
 func (m *Mock) AdvanceNext() (time.Duration, AdvanceWaiter) {
+	m.tb.Helper()
+	d, _, w := m.advanceNext()
+	return d, w
+}
+
+// AdvanceNextEvents is like AdvanceNext, but also returns the events the advance fires, with their
+// kinds and tags, so that a test driving the clock in a loop can branch on which timer fired, e.g.
+// to stop once the "shutdown" timer fires. To decide before advancing, use PeekN.
+func (m *Mock) AdvanceNextEvents() (time.Duration, []EventInfo, AdvanceWaiter) {
+	m.tb.Helper()
+	return m.advanceNext()
+}
+
+func (m *Mock) advanceNext() (time.Duration, []EventInfo, AdvanceWaiter) {
+	defer m.settle()
+	m.runBeforeAdvance()
 	m.mu.Lock()
 	if !m.testOver {
-		m.logger.Logf("Mock Clock - AdvanceNext()")
+		m.logfLocked("AdvanceNext()")
 	}
+	c := newCall(clockFunctionAdvanceNext, nil)
+	defer close(c.complete)
+	m.matchHarnessCallLocked(c)
 	m.tb.Helper()
-	w := AdvanceWaiter{tb: m.tb, ch: make(chan struct{})}
+	w := newAdvanceWaiter(m)
 	if m.nextTime.IsZero() {
 		defer close(w.ch)
 		defer m.mu.Unlock()
 		m.tb.Error("cannot AdvanceNext because there are no timers or tickers running")
-		return 0, w
+		return 0, nil, w
 	}
+	d, infos := m.advanceNextLocked(w)
+	return d, infos, w
+}
+
+// advanceNextLocked moves the clock to the next event, which must exist, and fires it, handing the
+// lock to the advance. It returns the duration advanced, and the events fired.
+func (m *Mock) advanceNextLocked(w AdvanceWaiter) (time.Duration, []EventInfo) {
 	d := m.nextTime.Sub(m.cur)
+	infos := make([]EventInfo, 0, len(m.nextEvents))
+	for _, e := range m.nextEvents {
+		infos = append(infos, e.info())
+	}
 	m.cur = m.nextTime
 	go m.advanceLocked(w)
-	return d, w
+	return d, infos
+}
+
+// AdvanceNextWithin advances the clock to the next timer or tick event, like AdvanceNext, if it is
+// scheduled within limit of the current time. Otherwise, it advances the clock by limit without firing
+// anything. It returns the duration the clock was advanced, a waiter for the events that fired, and
+// whether the next event was within limit. This allows simulations that mix event-driven and
+// fixed-step advancement to stop at each sampling point:
+//
+//	for mClock.Now().Before(end) {
+//		_, w, _ := mClock.AdvanceNextWithin(time.Minute)
+//		w.MustWait(ctx)
+//		sample()
+//	}
+//
+// Unlike AdvanceNext, it does not fail the test if there are no events scheduled, even with
+// WithFailOnIdleAdvance. It is trapped by Advance traps when it advances by limit, and by
+// AdvanceNext traps otherwise.
+func (m *Mock) AdvanceNextWithin(limit time.Duration) (time.Duration, AdvanceWaiter, bool) {
+	m.tb.Helper()
+	defer m.settle()
+	m.runBeforeAdvance()
+	w := newAdvanceWaiter(m)
+	m.mu.Lock()
+	if !m.testOver {
+		m.logfLocked("AdvanceNextWithin(%s)", limit)
+	}
+	c := newCall(clockFunctionAdvance, nil, withDuration(limit))
+	if m.nextWithinLocked(limit) {
+		c = newCall(clockFunctionAdvanceNext, nil)
+	}
+	defer close(c.complete)
+	m.matchHarnessCallLocked(c)
+	// the lock is released while the call is trapped, so decide again.
+	if !m.nextWithinLocked(limit) {
+		m.advanceByLocked(w, limit)
+		return limit, w, false
+	}
+	d, _ := m.advanceNextLocked(w)
+	return d, w, true
+}
+
+// nextWithinLocked returns whether the next event is scheduled within limit of the current time.
+func (m *Mock) nextWithinLocked(limit time.Duration) bool {
+	return !m.nextTime.IsZero() && !m.nextTime.After(addDuration(m.cur, limit))
+}
+
+// AdvanceNextN calls AdvanceNext n times, waiting for the timer/tick event(s) of each advance to
+// finish before the next. Events scheduled for the same time fire together, and count as one
+// advance. It returns the total duration the clock was advanced, and the events that fired, in
+// order. Like AdvanceNext, it fails the test if there are no events scheduled.
+//
+// If the context expires while waiting, it returns the context error, along with the duration
+// advanced and events fired so far.
+func (m *Mock) AdvanceNextN(ctx context.Context, n int) (time.Duration, []EventInfo, error) {
+	m.tb.Helper()
+	var total time.Duration
+	var events []EventInfo
+	for i := 0; i < n; i++ {
+		d, w := m.AdvanceNext()
+		total += d
+		err := w.Wait(ctx)
+		events = append(events, w.Events()...)
+		if err != nil {
+			return total, events, err
+		}
+		if d == 0 && len(w.Events()) == 0 {
+			// nothing was scheduled, and AdvanceNext failed the test.
+			break
+		}
+	}
+	return total, events, nil
+}
+
+// ErrConditionNotMet is returned by AdvanceUntil if the condition is not met within the budget.
+var ErrConditionNotMet = errors.New("condition not met")
+
+// AdvanceUntil calls AdvanceNext, and waits for the events to complete, until cond returns true.
+// cond is checked before each advance. It returns an error wrapping ErrConditionNotMet if the next
+// event would take the clock more than limit past its time when AdvanceUntil was called, or there are
+// no events scheduled, without advancing further. If ctx expires while waiting for events, it
+// returns the context error.
+func (m *Mock) AdvanceUntil(ctx context.Context, cond func() bool, limit time.Duration) error {
+	m.tb.Helper()
+	var elapsed time.Duration
+	for !cond() {
+		d, ok := m.Peek()
+		if !ok {
+			return fmt.Errorf("%w after advancing %s: no timers or tickers running", ErrConditionNotMet, elapsed)
+		}
+		if elapsed+d > limit {
+			return fmt.Errorf("%w within %s: next event is in %s", ErrConditionNotMet, limit, elapsed+d)
+		}
+		d, w := m.AdvanceNext()
+		elapsed += d
+		if err := w.Wait(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runToIdleLimit is the number of advances after which RunToIdle gives up.
+const runToIdleLimit = 10000
+
+// RunToIdle calls AdvanceNext, and waits for the events to complete, until no events remain, e.g.
+// to drain cleanup timers and backoff chains at the end of a test. It returns the total duration
+// the clock was advanced. Since running tickers never become idle, it gives up and returns an error
+// after 10000 advances, describing the events still pending. If ctx expires while waiting for
+// events, it returns the context error.
+func (m *Mock) RunToIdle(ctx context.Context) (time.Duration, error) {
+	m.tb.Helper()
+	var elapsed time.Duration
+	for i := 0; i < runToIdleLimit; i++ {
+		if _, ok := m.Peek(); !ok {
+			return elapsed, nil
+		}
+		d, w := m.AdvanceNext()
+		elapsed += d
+		if err := w.Wait(ctx); err != nil {
+			return elapsed, err
+		}
+	}
+	pending := m.PeekN(10)
+	return elapsed, fmt.Errorf("not idle after %d advances over %s; next events: %v",
+		runToIdleLimit, elapsed, pending)
+}
+
+// BeforeAdvance registers f to be called with the current time at the start of each method that
+// advances the clock, like Advance, AdvanceTo and AdvanceNext, before it looks at the scheduled
+// events. Adapters for other clock libraries use it to schedule events on the Mock for timers it
+// can't see, e.g. those reset by the library since they were created.
+func (m *Mock) BeforeAdvance(f func(now time.Time)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.beforeAdvance = append(m.beforeAdvance, f)
+}
+
+func (m *Mock) runBeforeAdvance() {
+	m.mu.Lock()
+	hooks := m.beforeAdvance
+	now := m.cur
+	m.mu.Unlock()
+	for _, f := range hooks {
+		f(now)
+	}
+}
+
+// settle waits for the code under test to quiesce after an advance, if configured with
+// WithSynctest.
+func (m *Mock) settle() {
+	m.mu.Lock()
+	quiesce := m.quiesce
+	m.mu.Unlock()
+	if quiesce != nil {
+		quiesce()
+	}
+}
+
+// WaitForCall blocks until n calls into the Clock, or the Timers and Tickers it created, have been
+// made with the given tag since the Mock was created, or the context expires. Since calls made
+// before WaitForCall count, tests waiting for the next call take a count first:
+//
+//	n := mClock.CallCount("poll")
+//	// ... trigger the code under test ...
+//	err := mClock.WaitForCall(ctx, "poll", n+1)
+//
+// Unlike a trap, it does not block the calls, so it is a lightweight way for tests to wait for the
+// code under test to reach a point that only needs ordering, not interception.
+func (m *Mock) WaitForCall(ctx context.Context, tag string, n int) error {
+	for {
+		m.mu.Lock()
+		if m.calledTags[tag] >= n {
+			m.mu.Unlock()
+			return nil
+		}
+		if m.callMade == nil {
+			m.callMade = make(chan struct{})
+		}
+		made := m.callMade
+		m.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-made:
+		}
+	}
+}
+
+// CallCount returns the number of calls made with the given tag so far, see WaitForCall.
+func (m *Mock) CallCount(tag string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.calledTags[tag]
+}
+
+func (m *Mock) recordCallTagsLocked(tags []string) {
+	if m.calledTags == nil {
+		m.calledTags = make(map[string]int)
+	}
+	for _, tag := range tags {
+		m.calledTags[tag]++
+	}
+	if m.callMade != nil {
+		close(m.callMade)
+		m.callMade = nil
+	}
 }
 
 // Peek returns the duration until the next ticker or timer event and the value
@@ -376,82 +1367,332 @@ func (m *Mock) Peek() (d time.Duration, ok bool) {
 	return m.nextTime.Sub(m.cur), true
 }
 
+// WaitForEvents blocks until at least n timer or tick events whose tags include all the given tags
+// are scheduled, or the context expires. This allows tests to wait for the code under test to
+// start its timers before advancing the clock, without trapping the calls.
+//
+// Only events scheduled by the code under test count: timers, tickers, TickerFunc, TimerFunc and
+// AdaptiveTickerFunc, and the deadlines of WithTimeout and WithDeadline. Events the test schedules
+// itself, with At, Breakpoint or Trap.ReleaseAfter, do not.
+func (m *Mock) WaitForEvents(ctx context.Context, n int, tags ...string) error {
+	for {
+		m.mu.Lock()
+		count := 0
+		for _, e := range m.all {
+			if _, ok := e.(*userEvent); ok {
+				continue
+			}
+			if containsAll(e.info().Tags, tags) {
+				count++
+			}
+		}
+		if count >= n {
+			m.mu.Unlock()
+			return nil
+		}
+		if m.eventAdded == nil {
+			m.eventAdded = make(chan struct{})
+		}
+		added := m.eventAdded
+		m.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-added:
+		}
+	}
+}
+
+// Events returns every scheduled timer, ticker and other event, with its kind, deadline, duration
+// or period, and tags, in the order they will fire. It is PeekN without a limit, for debugging
+// complex schedules and asserting on the whole queue.
+func (m *Mock) Events() []EventInfo {
+	return m.PeekN(math.MaxInt)
+}
+
+// PeekN returns the next n scheduled timer and tick events, in the order they will fire, without
+// advancing the clock. It returns fewer than n events if fewer are scheduled. A ticker appears
+// once, for its next tick. The duration until an event is its Deadline minus Now().
+func (m *Mock) PeekN(n int) []EventInfo {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.peekNLocked(n)
+}
+
+// peekNLocked returns the next n scheduled events, as for PeekN.
+func (m *Mock) peekNLocked(n int) []EventInfo {
+	events := slices.Clone(m.all)
+	slices.SortStableFunc(events, func(a, b event) int {
+		return a.next().Compare(b.next())
+	})
+	if len(events) > n {
+		events = events[:n]
+	}
+	infos := make([]EventInfo, 0, len(events))
+	for _, e := range events {
+		infos = append(infos, e.info())
+	}
+	return infos
+}
+
 // Trapper allows the creation of Traps
 type Trapper struct {
 	// mock is the underlying Mock.  This is a thin wrapper around Mock so that
 	// we can have our interface look like mClock.Trap().NewTimer("foo")
 	mock *Mock
+
+	priority        int
+	durationMatcher func(time.Duration) bool
+}
+
+// WithPriority returns a Trapper that creates traps with the given priority. When more than one
+// trap matches a call, the call is delivered to the matching traps in order of descending
+// priority: traps with a lower priority only catch the call once every trap with a higher
+// priority has released it. Traps with equal priority catch the call simultaneously, and must be
+// released from different goroutines. The default priority is zero.
+//
+// This allows, for example, a test harness to install a low priority trap that observes every
+// call, while the test itself installs higher priority traps to gate specific calls:
+//
+//	harness := mClock.Trap().WithPriority(-1).Now()
+//	test := mClock.Trap().Now("foo")
+func (t Trapper) WithPriority(p int) Trapper {
+	t.priority = p
+	return t
+}
+
+// WithDurationMatcher returns a Trapper that creates traps that, in addition to matching tags, only
+// match calls whose Duration satisfies match. This allows trapping, say, only a 30 second heartbeat
+// timer, while letting short timers created by the same code through, without tagging every call
+// site:
+//
+//	trap := mClock.Trap().WithDurationMatcher(func(d time.Duration) bool {
+//		return d == 30*time.Second
+//	}).NewTimer()
+//
+// Calls to methods that don't take a duration, like Now, have a zero Duration.
+func (t Trapper) WithDurationMatcher(match func(d time.Duration) bool) Trapper {
+	t.durationMatcher = match
+	return t
 }
 
 func (t Trapper) NewTimer(tags ...string) *Trap {
-	return t.mock.newTrap(clockFunctionNewTimer, tags)
+	return t.newTrap(clockFunctionNewTimer, tags)
+}
+
+func (t Trapper) After(tags ...string) *Trap {
+	return t.newTrap(clockFunctionAfter, tags)
+}
+
+func (t Trapper) Sleep(tags ...string) *Trap {
+	return t.newTrap(clockFunctionSleep, tags)
+}
+
+func (t Trapper) SleepContext(tags ...string) *Trap {
+	return t.newTrap(clockFunctionSleepContext, tags)
+}
+
+func (t Trapper) WithTimeout(tags ...string) *Trap {
+	return t.newTrap(clockFunctionWithTimeout, tags)
+}
+
+func (t Trapper) WithDeadline(tags ...string) *Trap {
+	return t.newTrap(clockFunctionWithDeadline, tags)
+}
+
+func (t Trapper) NewTimerInto(tags ...string) *Trap {
+	return t.newTrap(clockFunctionNewTimerInto, tags)
 }
 
 func (t Trapper) AfterFunc(tags ...string) *Trap {
-	return t.mock.newTrap(clockFunctionAfterFunc, tags)
+	return t.newTrap(clockFunctionAfterFunc, tags)
 }
 
 func (t Trapper) TimerStop(tags ...string) *Trap {
-	return t.mock.newTrap(clockFunctionTimerStop, tags)
+	return t.newTrap(clockFunctionTimerStop, tags)
 }
 
 func (t Trapper) TimerReset(tags ...string) *Trap {
-	return t.mock.newTrap(clockFunctionTimerReset, tags)
+	return t.newTrap(clockFunctionTimerReset, tags)
 }
 
 func (t Trapper) TickerFunc(tags ...string) *Trap {
-	return t.mock.newTrap(clockFunctionTickerFunc, tags)
+	return t.newTrap(clockFunctionTickerFunc, tags)
 }
 
 func (t Trapper) TickerFuncWait(tags ...string) *Trap {
-	return t.mock.newTrap(clockFunctionTickerFuncWait, tags)
+	return t.newTrap(clockFunctionTickerFuncWait, tags)
+}
+
+func (t Trapper) TickerFuncStop(tags ...string) *Trap {
+	return t.newTrap(clockFunctionTickerFuncStop, tags)
+}
+
+func (t Trapper) TimerFunc(tags ...string) *Trap {
+	return t.newTrap(clockFunctionTimerFunc, tags)
+}
+
+func (t Trapper) TimerFuncWait(tags ...string) *Trap {
+	return t.newTrap(clockFunctionTimerFuncWait, tags)
+}
+
+// AdaptiveTickerFunc traps calls to AdaptiveTickerFunc, and each subsequent computation of the
+// interval, with the computed interval as the Duration of the Call.
+func (t Trapper) AdaptiveTickerFunc(tags ...string) *Trap {
+	return t.newTrap(clockFunctionAdaptiveTickerFunc, tags)
+}
+
+func (t Trapper) AdaptiveTickerFuncWait(tags ...string) *Trap {
+	return t.newTrap(clockFunctionAdaptiveTickerFuncWait, tags)
+}
+
+func (t Trapper) AdaptiveTickerFuncStop(tags ...string) *Trap {
+	return t.newTrap(clockFunctionAdaptiveTickerFuncStop, tags)
 }
 
 func (t Trapper) NewTicker(tags ...string) *Trap {
-	return t.mock.newTrap(clockFunctionNewTicker, tags)
+	return t.newTrap(clockFunctionNewTicker, tags)
 }
 
 func (t Trapper) TickerStop(tags ...string) *Trap {
-	return t.mock.newTrap(clockFunctionTickerStop, tags)
+	return t.newTrap(clockFunctionTickerStop, tags)
 }
 
 func (t Trapper) TickerReset(tags ...string) *Trap {
-	return t.mock.newTrap(clockFunctionTickerReset, tags)
+	return t.newTrap(clockFunctionTickerReset, tags)
 }
 
 func (t Trapper) Now(tags ...string) *Trap {
-	return t.mock.newTrap(clockFunctionNow, tags)
+	return t.newTrap(clockFunctionNow, tags)
 }
 
 func (t Trapper) Since(tags ...string) *Trap {
-	return t.mock.newTrap(clockFunctionSince, tags)
+	return t.newTrap(clockFunctionSince, tags)
 }
 
 func (t Trapper) Until(tags ...string) *Trap {
-	return t.mock.newTrap(clockFunctionUntil, tags)
+	return t.newTrap(clockFunctionUntil, tags)
+}
+
+// Advance traps calls to Mock.Advance, with the requested duration as the Duration of the Call.
+// Like the AdvanceNext and Set traps, it is meant for testing harnesses built on top of the Mock,
+// which drive the clock themselves.
+func (t Trapper) Advance() *Trap {
+	return t.newTrap(clockFunctionAdvance, nil)
+}
+
+// AdvanceNext traps calls to Mock.AdvanceNext.
+func (t Trapper) AdvanceNext() *Trap {
+	return t.newTrap(clockFunctionAdvanceNext, nil)
+}
+
+// Set traps calls to Mock.Set, with the target time as the Time of the Call.
+func (t Trapper) Set() *Trap {
+	return t.newTrap(clockFunctionSet, nil)
+}
+
+// matchHarnessCallLocked matches a call to a method of the Mock, like Advance, against the traps,
+// if any trap the method. Unlike calls by the code under test, the call is not logged unless it is
+// trapped.
+func (m *Mock) matchHarnessCallLocked(c *apiCall) {
+	for _, t := range m.traps {
+		if t.fn == c.fn {
+			m.matchCallLocked(c)
+			return
+		}
+	}
 }
 
 func (m *Mock) Trap() Trapper {
-	return Trapper{m}
+	return Trapper{mock: m}
 }
 
-func (m *Mock) newTrap(fn clockFunction, tags []string) *Trap {
+func (t Trapper) newTrap(fn clockFunction, tags []string) *Trap {
+	m := t.mock
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	if !m.testOver {
-		m.logger.Logf("Mock Clock - Trap %s(..., %v)", fn, tags)
+		m.logfTaggedLocked(tags, "Trap %s(..., %v)", fn, tags)
 	}
+	m.checkTagsLocked("Trap "+fn.String(), tags)
 	tr := &Trap{
-		fn:    fn,
-		tags:  tags,
-		mock:  m,
-		calls: make(chan *apiCall),
-		done:  make(chan struct{}),
+		fn:       fn,
+		tags:     tags,
+		mock:     m,
+		priority: t.priority,
+		matchD:   t.durationMatcher,
+		calls:    make(chan *apiCall),
+		done:     make(chan struct{}),
 	}
 	m.traps = append(m.traps, tr)
+	if m.testCtx != nil {
+		// close the trap at the end of the test, if the test doesn't.
+		m.tb.Cleanup(func() {
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			select {
+			case <-tr.done:
+			default:
+				tr.closeLocked()
+			}
+		})
+	}
 	return tr
 }
 
+// logfLocked logs a message about the Mock, prefixed with the current time and the time elapsed
+// since the previous message, so that operations can be correlated with the events they trigger.
+func (m *Mock) logfLocked(format string, args ...any) {
+	m.logfTaggedLocked(nil, format, args...)
+}
+
+// logfTaggedLocked logs a message about an operation with the given tags, to the logger registered
+// for them with WithLoggerFor, if any.
+func (m *Mock) logfTaggedLocked(tags []string, format string, args ...any) {
+	delta := m.cur.Sub(m.lastLog)
+	m.lastLog = m.cur
+	sign := "+"
+	if delta < 0 {
+		sign = ""
+	}
+	msg := fmt.Sprintf("Mock Clock - [%s %s%s] "+format,
+		append([]any{m.cur.Format(time.RFC3339Nano), sign, delta}, args...)...)
+	m.history.add(msg)
+	m.loggerForLocked(tags).Logf("%s", msg)
+}
+
+// tagLogger is a logger for operations with a tag, see WithLoggerFor.
+type tagLogger struct {
+	tag    string
+	logger Logger
+}
+
+// WithLoggerFor sends log messages about operations tagged with tag, like calls and the traps that
+// match them, to l rather than the Mock's logger. This keeps the clock chatter of a noisy subsystem
+// out of the main test log:
+//
+//	quartz.NewMock(t).WithLoggerFor("sync", syncLogger)
+//
+// If an operation has several tags with loggers, the logger registered first is used. Since some
+// messages no longer reach the test log, the recent messages are logged if the test fails, as with
+// WithLogger.
+func (m *Mock) WithLoggerFor(tag string, l Logger) *Mock {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tagLoggers = append(m.tagLoggers, tagLogger{tag: tag, logger: l})
+	m.customLogger = true
+	return m
+}
+
+func (m *Mock) loggerForLocked(tags []string) Logger {
+	for _, tl := range m.tagLoggers {
+		if slices.Contains(tags, tl.tag) {
+			return tl.logger
+		}
+	}
+	return m.logger
+}
+
 // WithLogger replaces the default testing logger with a custom one.
 //
 // This can be used to discard log messages with:
@@ -461,46 +1702,243 @@ func (m *Mock) WithLogger(l Logger) *Mock {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.logger = l
+	m.customLogger = true
+	return m
+}
+
+// WithContextTags configures the Mock to append tags derived from context values to calls of
+// methods that take a context, like TickerFunc and TimerFunc. For each key whose value is set on the
+// context, the value formatted with fmt.Sprint is appended to the tags of the call. This allows
+// traps to match on values like request IDs or component names without threading tags through
+// deep call stacks:
+//
+//	mClock := quartz.NewMock(t).WithContextTags(componentKey{})
+//	trap := mClock.Trap().TickerFunc("reaper")
+//	// matches TickerFunc calls whose ctx has componentKey{} set to "reaper"
+func (m *Mock) WithContextTags(keys ...any) *Mock {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.contextTagKeys = keys
+	return m
+}
+
+// withTestContext returns a context that is canceled when either ctx is canceled or the test
+// ends, if the TB provides a Context. This ensures that TickerFunc and TimerFunc don't outlive the
+// test, even if their ctx is never canceled.
+func (m *Mock) withTestContext(ctx context.Context) (context.Context, func()) {
+	if m.testCtx == nil {
+		return ctx, func() {}
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	stop := context.AfterFunc(m.testCtx, cancel)
+	return ctx, func() {
+		stop()
+		cancel()
+	}
+}
+
+// contextTagsLocked returns tags with the tags derived from ctx appended.
+func (m *Mock) contextTagsLocked(ctx context.Context, tags []string) []string {
+	if len(m.contextTagKeys) == 0 {
+		return tags
+	}
+	tags = slices.Clip(tags)
+	for _, k := range m.contextTagKeys {
+		if v := ctx.Value(k); v != nil {
+			tags = append(tags, fmt.Sprint(v))
+		}
+	}
+	return tags
+}
+
+// WithFailOnIdleAdvance causes the Mock to fail the test if Advance or AdvanceBatch is called
+// while no timer or tick events are scheduled. In many tests, that means the code under test never
+// started its timer, and the test is passing vacuously. AdvanceNext always fails the test in this
+// case.
+func (m *Mock) WithFailOnIdleAdvance() *Mock {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.failOnIdleAdvance = true
+	return m
+}
+
+func (m *Mock) checkIdleAdvanceLocked(fn string) {
+	if m.failOnIdleAdvance && m.nextTime.IsZero() {
+		m.tb.Helper()
+		m.tb.Errorf("cannot %s because there are no timers or tickers running", fn)
+	}
+}
+
+// WithAutoIncrement enables a mode where each call to Now advances the Mock's time by step after
+// returning, so that successive calls return strictly increasing times without explicit calls to
+// Advance. This is useful for testing code that requires unique, increasing timestamps, like
+// ordering keys or ULIDs.
+//
+// Now never advances the time past the next timer or tick event; once the time reaches it, Now
+// returns the same time until the event is fired via Advance, AdvanceNext, etc. A step of zero
+// disables the mode.
+func (m *Mock) WithAutoIncrement(step time.Duration) *Mock {
+	if step < 0 {
+		panic("WithAutoIncrement called with negative step")
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.autoIncrement = step
+	return m
+}
+
+// WithMonotonic controls whether times returned by Now carry a monotonic clock reading, like times
+// returned by time.Now do. By default, they don't, like times that have been through serialization
+// or Round(0). This allows code that round-trips times to be tested against both kinds of values.
+//
+// The monotonic reading advances exactly with the Mock's time. Note that the time package only
+// attaches monotonic readings to times in the local time zone, so the returned times are in
+// time.Local.
+func (m *Mock) WithMonotonic(enabled bool) *Mock {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.monoAnchor = time.Time{}
+	if enabled {
+		m.monoAnchor = time.Now()
+	}
+	return m
+}
+
+// withMonotonicLocked returns t with a monotonic reading, if enabled by WithMonotonic.
+func (m *Mock) withMonotonicLocked(t time.Time) time.Time {
+	if m.monoAnchor.IsZero() {
+		return t
+	}
+	// t has no monotonic reading, so Sub uses the wall clock, and adding it to the anchor gives
+	// the same wall time as t, with the anchor's monotonic reading offset by the same amount.
+	return m.monoAnchor.Add(t.Sub(m.monoAnchor))
+}
+
+// WithStartTime sets the time of the Mock to t, as if it had been created at t, so that tests can
+// begin at realistic timestamps, e.g. just before a daylight saving change or the end of a month.
+// Unlike Set, the time before t doesn't count towards the time simulated by the test. It overrides
+// the start time from QUARTZ_EPOCH, and fails the test if any timers or tickers are running.
+func (m *Mock) WithStartTime(t time.Time) *Mock {
+	m.tb.Helper()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.nextTime.IsZero() {
+		m.tb.Error("WithStartTime called after timers/tickers started")
+		return m
+	}
+	if !m.testOver {
+		m.logfLocked("WithStartTime(%s)", t)
+	}
+	m.cur = t
+	m.origin = t
+	m.lastLog = t
+	m.resetMonotonicityLocked()
 	return m
 }
 
-// NewMock creates a new Mock with the time set to midnight UTC on Jan 1, 2024.
-// You may re-set the time earlier than this, but only before timers or tickers
-// are created.
+// EpochEnv is the environment variable consulted for the start time of new Mocks, in RFC 3339
+// format. It allows failures that depend on the wall-clock era, like month boundaries and leap
+// years, to be replayed identically in CI and locally.
+const EpochEnv = "QUARTZ_EPOCH"
+
+// NewMock creates a new Mock with the time set to midnight UTC on Jan 1, 2024, or to the time in the
+// QUARTZ_EPOCH environment variable, if set. You may re-set the time earlier than this, but only
+// before timers or tickers are created.
+//
+// If tb provides a Context, as testing.T does from Go 1.24, the test's context bounds the lifetime
+// of the Mock's TickerFuncs and TimerFuncs, which exit when it is canceled, and of traps, which are
+// closed at the end of the test if still open.
 func NewMock(tb testing.TB) *Mock {
 	cur, err := time.Parse(time.RFC3339, "2024-01-01T00:00:00Z")
 	if err != nil {
 		panic(err)
 	}
+	if v := os.Getenv(EpochEnv); v != "" {
+		epoch, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			tb.Errorf("invalid %s %q: %s", EpochEnv, v, err)
+		} else {
+			cur = epoch
+			tb.Logf("Mock Clock - using start time %s (from %s)", cur, EpochEnv)
+		}
+	}
 	m := &Mock{
-		tb:     tb,
-		logger: tb,
-		cur:    cur,
+		tb:        tb,
+		logger:    tb,
+		cur:       cur,
+		origin:    cur,
+		realStart: time.Now(),
+		lastLog:   cur,
+	}
+	if c, ok := tb.(interface{ Context() context.Context }); ok {
+		m.testCtx = c.Context()
 	}
 	tb.Cleanup(func() {
 		m.mu.Lock()
 		defer m.mu.Unlock()
+		if tb.Failed() {
+			m.dumpTimelineLocked()
+		}
 		m.testOver = true
-		m.logger.Logf("Mock Clock - test cleanup; will no longer log clock events")
+		m.logfLocked("test cleanup; will no longer log clock events")
 	})
 	return m
 }
 
 var _ Clock = &Mock{}
 
+// String summarizes the state of the Mock: its time, scheduled events and open traps.
+func (m *Mock) String() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	next := "none"
+	if !m.nextTime.IsZero() {
+		next = m.nextTime.Sub(m.cur).String()
+	}
+	return fmt.Sprintf("Mock(%s, %d events, next in %s, %d traps)",
+		m.cur.Format(time.RFC3339Nano), len(m.all), next, len(m.traps))
+}
+
+// LogValue implements slog.LogValuer.
+func (m *Mock) LogValue() slog.Value {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	attrs := []slog.Attr{
+		slog.Time("now", m.cur),
+		slog.Int("events", len(m.all)),
+		slog.Int("traps", len(m.traps)),
+	}
+	if !m.nextTime.IsZero() {
+		attrs = append(attrs, slog.Time("next", m.nextTime))
+	}
+	return slog.GroupValue(attrs...)
+}
+
 type mockTickerFunc struct {
-	ctx  context.Context
-	d    time.Duration
-	f    func() error
-	nxt  time.Time
-	mock *Mock
+	ctx context.Context
+	// release releases the resources tying ctx to the test, once the function exits.
+	release func()
+	d       time.Duration
+	tags    []string
+	f       func() error
+	nxt     time.Time
+	mock    *Mock
+
+	// interval computes the duration until the next tick, for AdaptiveTickerFunc, or is nil.
+	interval func(n int, last error) time.Duration
+	// n is the number of calls of f so far, for AdaptiveTickerFunc.
+	n int
 
 	// cond is a condition Locked on the main Mock.mu
 	cond *sync.Cond
 	// inProgress is true when we are actively calling f
 	inProgress bool
+	// stopped is true when Stop has been called
+	stopped bool
 	// done is true when the ticker exits
 	done bool
+	// exited is closed when done is set, to stop waiting on the context
+	exited chan struct{}
 	// err holds the error when the ticker exits
 	err error
 }
@@ -509,13 +1947,44 @@ func (m *mockTickerFunc) next() time.Time {
 	return m.nxt
 }
 
-func (m *mockTickerFunc) fire(_ time.Time) {
+func (m *mockTickerFunc) info() EventInfo {
+	return EventInfo{
+		Kind:     m.kind(clockFunctionTickerFunc).String(),
+		Tags:     m.tags,
+		Deadline: m.nxt,
+		Duration: m.d,
+	}
+}
+
+// kind returns fn, which is one of the TickerFunc clockFunctions, or the corresponding
+// AdaptiveTickerFunc clockFunction if this is an adaptive ticker.
+func (m *mockTickerFunc) kind(fn clockFunction) clockFunction {
+	if m.interval == nil {
+		return fn
+	}
+	switch fn {
+	case clockFunctionTickerFuncWait:
+		return clockFunctionAdaptiveTickerFuncWait
+	case clockFunctionTickerFuncStop:
+		return clockFunctionAdaptiveTickerFuncStop
+	default:
+		return clockFunctionAdaptiveTickerFunc
+	}
+}
+
+func (m *mockTickerFunc) fire(tt time.Time) {
+	if m.interval != nil {
+		m.fireAdaptive(tt)
+		return
+	}
 	m.mock.mu.Lock()
 	if m.done {
 		m.mock.mu.Unlock()
 		return
 	}
-	m.nxt = m.nxt.Add(m.d)
+	for !m.nxt.After(m.mock.cur) {
+		m.nxt = addDuration(m.nxt, m.d)
+	}
 	m.mock.recomputeNextLocked()
 	// we need this check to happen after we've computed the next tick,
 	// otherwise it will be immediately rescheduled.
@@ -533,7 +2002,57 @@ func (m *mockTickerFunc) fire(_ time.Time) {
 	m.cond.Broadcast() // wake up anything waiting for f to finish
 	if err != nil {
 		m.exitLocked(err)
+		return
+	}
+	if m.stopped {
+		m.exitLocked(ErrStopped)
+	}
+}
+
+// fireAdaptive calls f, then computes the interval until the next tick and reschedules. Unlike
+// a regular TickerFunc, the next tick is not scheduled until f returns, and errors returned by f
+// are passed to the interval function rather than stopping the ticker.
+func (m *mockTickerFunc) fireAdaptive(_ time.Time) {
+	m.mock.mu.Lock()
+	if m.done || m.inProgress {
+		m.mock.mu.Unlock()
+		return
+	}
+	m.mock.removeEventLocked(m)
+	m.inProgress = true
+	m.mock.mu.Unlock()
+	err := m.f()
+	m.mock.mu.Lock()
+	m.n++
+	n := m.n
+	m.mock.mu.Unlock()
+	d := m.interval(n, err)
+	m.mock.mu.Lock()
+	defer m.mock.mu.Unlock()
+	m.inProgress = false
+	m.cond.Broadcast() // wake up anything waiting for f to finish
+	switch {
+	case m.done:
+		return
+	case m.stopped:
+		m.exitLocked(ErrStopped)
+		return
+	case m.ctx.Err() != nil:
+		m.exitLocked(m.ctx.Err())
+		return
+	case d <= 0:
+		m.exitLocked(err)
+		return
+	}
+	c := newCall(clockFunctionAdaptiveTickerFunc, m.tags, withDuration(d))
+	m.mock.matchCallLocked(c)
+	defer close(c.complete)
+	if m.done || m.stopped {
+		return
 	}
+	m.d = d
+	m.nxt = addDuration(m.mock.cur, m.mock.scheduleDurationLocked(c))
+	m.mock.addEventLocked(m)
 }
 
 func (m *mockTickerFunc) exitLocked(err error) {
@@ -542,12 +2061,18 @@ func (m *mockTickerFunc) exitLocked(err error) {
 	}
 	m.done = true
 	m.err = err
+	close(m.exited)
 	m.mock.removeEventLocked(m)
 	m.cond.Broadcast()
+	m.release()
 }
 
 func (m *mockTickerFunc) waitForCtx() {
-	<-m.ctx.Done()
+	select {
+	case <-m.ctx.Done():
+	case <-m.exited:
+		return
+	}
 	m.mock.mu.Lock()
 	defer m.mock.mu.Unlock()
 	for m.inProgress {
@@ -559,7 +2084,111 @@ func (m *mockTickerFunc) waitForCtx() {
 func (m *mockTickerFunc) Wait(tags ...string) error {
 	m.mock.mu.Lock()
 	defer m.mock.mu.Unlock()
-	c := newCall(clockFunctionTickerFuncWait, tags)
+	c := newCall(m.kind(clockFunctionTickerFuncWait), tags)
+	m.mock.matchCallLocked(c)
+	defer close(c.complete)
+	for !m.done {
+		m.cond.Wait()
+	}
+	return m.err
+}
+
+func (m *mockTickerFunc) Stop(tags ...string) {
+	m.mock.mu.Lock()
+	defer m.mock.mu.Unlock()
+	c := newCall(m.kind(clockFunctionTickerFuncStop), tags)
+	m.mock.matchCallLocked(c)
+	defer close(c.complete)
+	m.stopped = true
+	m.mock.removeEventLocked(m)
+	if !m.inProgress {
+		m.exitLocked(ErrStopped)
+	}
+}
+
+var _ StopWaiter = &mockTickerFunc{}
+
+type mockTimerFunc struct {
+	ctx context.Context
+	// release releases the resources tying ctx to the test, once the function exits.
+	release func()
+	d       time.Duration
+	tags    []string
+	f       func() error
+	nxt     time.Time
+	mock    *Mock
+
+	// cond is a condition Locked on the main Mock.mu
+	cond *sync.Cond
+	// inProgress is true when we are actively calling f
+	inProgress bool
+	// done is true when the timer exits, either by calling f or because the context expired
+	done bool
+	// exited is closed when done is set, to stop waiting on the context
+	exited chan struct{}
+	// err holds the error when the timer exits
+	err error
+}
+
+func (m *mockTimerFunc) next() time.Time {
+	return m.nxt
+}
+
+func (m *mockTimerFunc) info() EventInfo {
+	return EventInfo{Kind: clockFunctionTimerFunc.String(), Tags: m.tags, Deadline: m.nxt, Duration: m.d}
+}
+
+func (m *mockTimerFunc) fire(_ time.Time) {
+	m.mock.mu.Lock()
+	if m.done || m.inProgress {
+		m.mock.mu.Unlock()
+		return
+	}
+	if err := m.ctx.Err(); err != nil {
+		m.exitLocked(err)
+		m.mock.mu.Unlock()
+		return
+	}
+	m.mock.removeEventLocked(m)
+	m.inProgress = true
+	m.mock.mu.Unlock()
+	err := m.f()
+	m.mock.mu.Lock()
+	defer m.mock.mu.Unlock()
+	m.inProgress = false
+	m.exitLocked(err)
+}
+
+func (m *mockTimerFunc) exitLocked(err error) {
+	if m.done {
+		return
+	}
+	m.done = true
+	m.err = err
+	close(m.exited)
+	m.mock.removeEventLocked(m)
+	m.cond.Broadcast()
+	m.release()
+}
+
+func (m *mockTimerFunc) waitForCtx() {
+	select {
+	case <-m.ctx.Done():
+	case <-m.exited:
+		return
+	}
+	m.mock.mu.Lock()
+	defer m.mock.mu.Unlock()
+	for m.inProgress {
+		m.cond.Wait()
+	}
+	m.exitLocked(m.ctx.Err())
+}
+
+func (m *mockTimerFunc) Wait(tags ...string) error {
+	m.mock.mu.Lock()
+	defer m.mock.mu.Unlock()
+	c := newCall(clockFunctionTimerFuncWait, tags)
 	m.mock.matchCallLocked(c)
 	defer close(c.complete)
 	for !m.done {
@@ -568,29 +2197,56 @@ func (m *mockTickerFunc) Wait(tags ...string) error {
 	return m.err
 }
 
-var _ Waiter = &mockTickerFunc{}
+var _ Waiter = &mockTimerFunc{}
 
 type clockFunction int
 
 const (
 	clockFunctionNewTimer clockFunction = iota
+	clockFunctionNewTimerInto
+	clockFunctionAfter
+	clockFunctionSleep
+	clockFunctionSleepContext
+	clockFunctionWithTimeout
+	clockFunctionWithDeadline
 	clockFunctionAfterFunc
 	clockFunctionTimerStop
 	clockFunctionTimerReset
 	clockFunctionTickerFunc
 	clockFunctionTickerFuncWait
+	clockFunctionTickerFuncStop
+	clockFunctionTimerFunc
+	clockFunctionTimerFuncWait
+	clockFunctionAdaptiveTickerFunc
+	clockFunctionAdaptiveTickerFuncWait
+	clockFunctionAdaptiveTickerFuncStop
 	clockFunctionNewTicker
 	clockFunctionTickerReset
 	clockFunctionTickerStop
 	clockFunctionNow
 	clockFunctionSince
 	clockFunctionUntil
+	clockFunctionAdvance
+	clockFunctionAdvanceNext
+	clockFunctionSet
 )
 
 func (c clockFunction) String() string {
 	switch c {
 	case clockFunctionNewTimer:
 		return "NewTimer"
+	case clockFunctionNewTimerInto:
+		return "NewTimerInto"
+	case clockFunctionAfter:
+		return "After"
+	case clockFunctionSleep:
+		return "Sleep"
+	case clockFunctionSleepContext:
+		return "SleepContext"
+	case clockFunctionWithTimeout:
+		return "WithTimeout"
+	case clockFunctionWithDeadline:
+		return "WithDeadline"
 	case clockFunctionAfterFunc:
 		return "AfterFunc"
 	case clockFunctionTimerStop:
@@ -601,6 +2257,18 @@ func (c clockFunction) String() string {
 		return "TickerFunc"
 	case clockFunctionTickerFuncWait:
 		return "TickerFunc.Wait"
+	case clockFunctionTickerFuncStop:
+		return "TickerFunc.Stop"
+	case clockFunctionTimerFunc:
+		return "TimerFunc"
+	case clockFunctionTimerFuncWait:
+		return "TimerFunc.Wait"
+	case clockFunctionAdaptiveTickerFunc:
+		return "AdaptiveTickerFunc"
+	case clockFunctionAdaptiveTickerFuncWait:
+		return "AdaptiveTickerFunc.Wait"
+	case clockFunctionAdaptiveTickerFuncStop:
+		return "AdaptiveTickerFunc.Stop"
 	case clockFunctionNewTicker:
 		return "NewTicker"
 	case clockFunctionTickerReset:
@@ -613,6 +2281,12 @@ func (c clockFunction) String() string {
 		return "Since"
 	case clockFunctionUntil:
 		return "Until"
+	case clockFunctionAdvance:
+		return "Advance"
+	case clockFunctionAdvanceNext:
+		return "AdvanceNext"
+	case clockFunctionSet:
+		return "Set"
 	default:
 		return fmt.Sprintf("Unknown clockFunction(%d)", c)
 	}
@@ -626,15 +2300,42 @@ type apiCall struct {
 	Duration time.Duration
 	Tags     []string
 
+	// virtualTime and realTime are when the call was made, on the Mock and on the wall clock.
+	virtualTime time.Time
+	realTime    time.Time
+
 	fn       clockFunction
 	releases sync.WaitGroup
 	complete chan struct{}
+	// stageReleased is closed when every trap in the current priority level has released the
+	// call, or nil if there are no lower priority traps waiting to catch it.
+	stageReleased chan struct{}
+	// canceled is set if a trap canceled the call, rather than just releasing it.
+	canceled bool
+	// now, if set by ReleaseWith, is the current time the call uses instead of the Mock's.
+	now *time.Time
+	// f is the function passed to AfterFunc, possibly wrapped by a trap.
+	f func()
+	// delayedReleases are the releases scheduled by traps with ReleaseAfter.
+	delayedReleases map[*Trap]*delayedRelease
 }
 
 func (a *apiCall) String() string {
 	switch a.fn {
 	case clockFunctionNewTimer:
 		return fmt.Sprintf("NewTimer(%s, %v)", a.Duration, a.Tags)
+	case clockFunctionNewTimerInto:
+		return fmt.Sprintf("NewTimerInto(<ch>, %s, %v)", a.Duration, a.Tags)
+	case clockFunctionAfter:
+		return fmt.Sprintf("After(%s, %v)", a.Duration, a.Tags)
+	case clockFunctionSleep:
+		return fmt.Sprintf("Sleep(%s, %v)", a.Duration, a.Tags)
+	case clockFunctionSleepContext:
+		return fmt.Sprintf("SleepContext(<ctx>, %s, %v)", a.Duration, a.Tags)
+	case clockFunctionWithTimeout:
+		return fmt.Sprintf("WithTimeout(<ctx>, %s, %v)", a.Duration, a.Tags)
+	case clockFunctionWithDeadline:
+		return fmt.Sprintf("WithDeadline(<ctx>, %s, %v)", a.Time, a.Tags)
 	case clockFunctionAfterFunc:
 		return fmt.Sprintf("AfterFunc(%s, <fn>, %v)", a.Duration, a.Tags)
 	case clockFunctionTimerStop:
@@ -645,6 +2346,18 @@ func (a *apiCall) String() string {
 		return fmt.Sprintf("TickerFunc(<ctx>, %s, <fn>, %s)", a.Duration, a.Tags)
 	case clockFunctionTickerFuncWait:
 		return fmt.Sprintf("TickerFunc.Wait(%v)", a.Tags)
+	case clockFunctionTickerFuncStop:
+		return fmt.Sprintf("TickerFunc.Stop(%v)", a.Tags)
+	case clockFunctionTimerFunc:
+		return fmt.Sprintf("TimerFunc(<ctx>, %s, <fn>, %v)", a.Duration, a.Tags)
+	case clockFunctionTimerFuncWait:
+		return fmt.Sprintf("TimerFunc.Wait(%v)", a.Tags)
+	case clockFunctionAdaptiveTickerFunc:
+		return fmt.Sprintf("AdaptiveTickerFunc(<ctx>, %s, <fn>, %v)", a.Duration, a.Tags)
+	case clockFunctionAdaptiveTickerFuncWait:
+		return fmt.Sprintf("AdaptiveTickerFunc.Wait(%v)", a.Tags)
+	case clockFunctionAdaptiveTickerFuncStop:
+		return fmt.Sprintf("AdaptiveTickerFunc.Stop(%v)", a.Tags)
 	case clockFunctionNewTicker:
 		return fmt.Sprintf("NewTicker(%s, %v)", a.Duration, a.Tags)
 	case clockFunctionTickerReset:
@@ -657,6 +2370,12 @@ func (a *apiCall) String() string {
 		return fmt.Sprintf("Since(%s, %v)", a.Time, a.Tags)
 	case clockFunctionUntil:
 		return fmt.Sprintf("Until(%s, %v)", a.Time, a.Tags)
+	case clockFunctionAdvance:
+		return fmt.Sprintf("Advance(%s)", a.Duration)
+	case clockFunctionAdvanceNext:
+		return "AdvanceNext()"
+	case clockFunctionSet:
+		return fmt.Sprintf("Set(%s)", a.Time)
 	default:
 		return fmt.Sprintf("Unknown clockFunction(%d)", a.fn)
 	}
@@ -664,27 +2383,82 @@ func (a *apiCall) String() string {
 
 // Call represents an apiCall that has been trapped.
 type Call struct {
-	Time     time.Time
+	// Time is the time argument of the call, for calls that take one, like the reference time
+	// passed to Since and Until, or the deadline passed to WithDeadline.
+	Time time.Time
+	// Duration is the duration argument of the call, for calls that take one, like NewTimer.
 	Duration time.Duration
-	Tags     []string
+	// Tags are the tags the call was made with.
+	Tags []string
+
+	// VirtualTime is the time on the Mock when the call was made, and RealTime the wall clock
+	// time. Together they help debug interleavings between the test and the code under test.
+	VirtualTime time.Time
+	RealTime    time.Time
+
+	tb            testing.TB
+	apiCall       *apiCall
+	trap          *Trap
+	stageReleased chan struct{}
+	// released is set by Release, which may be called from a different goroutine than String or
+	// Record.
+	released atomic.Bool
+	// handling is set if the call was passed to a handler, see Trap.Handle.
+	handling *handling
+}
+
+// handling tracks whether a call was released while its trap's handler was running.
+type handling struct {
+	mu       sync.Mutex
+	active   bool
+	released bool
+}
+
+// String describes the trapped call, e.g. "NewTimer(1s, [tag])", and whether it was released.
+func (c *Call) String() string {
+	if c.released.Load() {
+		return c.apiCall.String() + " (released)"
+	}
+	return c.apiCall.String()
+}
 
-	tb      testing.TB
-	apiCall *apiCall
-	trap    *Trap
+// LogValue implements slog.LogValuer.
+func (c *Call) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("call", c.apiCall.String()),
+		slog.Bool("released", c.released.Load()),
+	)
 }
 
 // Release the call and wait for it to complete. If the provided context expires before the call completes, it returns
 // an error.
 //
 // IMPORTANT: If a call is trapped by more than one trap, they all must release the call before it can complete, and
-// they must do so from different goroutines.
+// they must do so from different goroutines. The exception is traps with a different priority (see
+// Trapper.WithPriority): releasing a call from a higher priority trap only waits until the call has been handed to
+// the lower priority traps.
 func (c *Call) Release(ctx context.Context) error {
+	if h := c.handling; h != nil {
+		h.mu.Lock()
+		if h.active {
+			// the call completes once the handler returns.
+			h.released = true
+			c.released.Store(true)
+			h.mu.Unlock()
+			c.trap.callReleased()
+			return nil
+		}
+		h.mu.Unlock()
+	}
+	c.released.Store(true)
 	c.apiCall.releases.Done()
 	select {
 	case <-ctx.Done():
 		return fmt.Errorf("timed out waiting for release; did more than one trap capture the call?: %w", ctx.Err())
 	case <-c.apiCall.complete:
 		// OK
+	case <-c.stageReleased:
+		// OK, lower priority traps will catch the call next.
 	}
 	c.trap.callReleased()
 	return nil
@@ -702,12 +2476,105 @@ func (c *Call) MustRelease(ctx context.Context) {
 	}
 }
 
+// ErrCancelNotSupported is returned when attempting to cancel a call that doesn't create a timer,
+// ticker, After channel, or AfterFunc.
+var ErrCancelNotSupported = errors.New("cancel not supported")
+
+// Cancel releases the call, but rejects the operation: NewTimer, NewTimerInto and NewTicker return
+// a stopped Timer or Ticker, After returns a channel that never delivers, and the function passed
+// to AfterFunc is never scheduled. This allows tests to simulate the operation being disabled
+// without changing the code under test. It is only supported on calls trapped by NewTimer,
+// NewTimerInto, NewTicker, After and AfterFunc traps, and otherwise returns ErrCancelNotSupported
+// without releasing the call.
+//
+// Like Release, it waits for the call to complete.
+func (c *Call) Cancel(ctx context.Context) error {
+	switch c.apiCall.fn {
+	case clockFunctionNewTimer, clockFunctionNewTimerInto, clockFunctionNewTicker, clockFunctionAfter,
+		clockFunctionAfterFunc:
+	default:
+		return fmt.Errorf("%w for %s", ErrCancelNotSupported, c.apiCall.fn)
+	}
+	c.apiCall.canceled = true
+	return c.Release(ctx)
+}
+
+// ErrReleaseWithNotSupported is returned when attempting to release a call with a substitute time
+// that doesn't read the current time.
+var ErrReleaseWithNotSupported = errors.New("release with substitute time not supported")
+
+// ReleaseWith releases the call like Release, but the call uses now as the current time instead of
+// the Mock's: Now returns it, and Since and Until measure from it. This simulates clock skew or a
+// stale cached time at a single call site, without moving the Mock, which would affect every other
+// timer. It is only supported on calls trapped by Now, Since and Until traps, and otherwise returns
+// ErrReleaseWithNotSupported without releasing the call.
+func (c *Call) ReleaseWith(ctx context.Context, now time.Time) error {
+	switch c.apiCall.fn {
+	case clockFunctionNow, clockFunctionSince, clockFunctionUntil:
+	default:
+		return fmt.Errorf("%w for %s", ErrReleaseWithNotSupported, c.apiCall.fn)
+	}
+	c.apiCall.now = &now
+	return c.Release(ctx)
+}
+
+// MustReleaseWith calls ReleaseWith, and fails the test immediately if there is an error.
+func (c *Call) MustReleaseWith(ctx context.Context, now time.Time) {
+	if err := c.ReleaseWith(ctx, now); err != nil {
+		c.tb.Helper()
+		c.tb.Fatal(err.Error())
+	}
+}
+
+// WrapFunc replaces the function passed to a trapped AfterFunc call with the result of calling
+// wrap on it. This allows tests to detect when the function is called, or inject delays, without
+// changing the code under test:
+//
+//	c := trap.MustWait(ctx)
+//	c.WrapFunc(func(orig func()) func() {
+//		return func() {
+//			close(called)
+//			orig()
+//		}
+//	})
+//	c.MustRelease(ctx)
+//
+// WrapFunc must be called before the call is released, and fails the test if the call is not an
+// AfterFunc call.
+func (c *Call) WrapFunc(wrap func(orig func()) func()) {
+	c.tb.Helper()
+	if c.apiCall.fn != clockFunctionAfterFunc {
+		c.tb.Errorf("cannot WrapFunc on %s call", c.apiCall.fn)
+		return
+	}
+	if c.released.Load() {
+		c.tb.Errorf("cannot WrapFunc on %s after it was released", c.apiCall)
+		return
+	}
+	c.apiCall.f = wrap(c.apiCall.f)
+}
+
+// MustCancel cancels the call and waits for it to complete. If the provided context expires before the call completes,
+// or the call cannot be canceled, it fails the test.
+func (c *Call) MustCancel(ctx context.Context) {
+	if err := c.Cancel(ctx); err != nil {
+		c.tb.Helper()
+		c.tb.Fatal(err.Error())
+	}
+}
+
 func withTime(t time.Time) callArg {
 	return func(c *apiCall) {
 		c.Time = t
 	}
 }
 
+func withFunc(f func()) callArg {
+	return func(c *apiCall) {
+		c.f = f
+	}
+}
+
 func withDuration(d time.Duration) callArg {
 	return func(c *apiCall) {
 		c.Duration = d
@@ -727,25 +2594,136 @@ func newCall(fn clockFunction, tags []string, args ...callArg) *apiCall {
 }
 
 type Trap struct {
-	fn    clockFunction
-	tags  []string
-	mock  *Mock
-	calls chan *apiCall
-	done  chan struct{}
-
-	// mu protects the unreleasedCalls count
+	fn       clockFunction
+	tags     []string
+	mock     *Mock
+	priority int
+	matchD   func(time.Duration) bool // see Trapper.WithDurationMatcher
+	calls    chan *apiCall
+	done     chan struct{}
+
+	// matched is the number of calls the trap matched, protected by mock.mu.
+	matched int
+	// releaseAfter, if set, is the delay after which calls are released, see ReleaseAfter.
+	// Protected by mock.mu.
+	releaseAfter *time.Duration
+	// autoRelease is the number of calls to release automatically, see AutoRelease, and
+	// autoReleased the calls released so far. Protected by mock.mu.
+	autoRelease  int
+	autoReleased []*Call
+
+	// mu protects the unreleasedCalls count, notify, handler and queued
 	mu              sync.Mutex
 	unreleasedCalls int
+	notify          chan *Call
+	handler         func(*Call)
+	// queued is the number of calls waiting to be received from calls, see Len.
+	queued int
+}
+
+// CloseTraps closes all the open traps of the Mock.
+func (m *Mock) CloseTraps() {
+	m.tb.Helper()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for len(m.traps) > 0 {
+		m.traps[0].closeLocked()
+	}
+}
+
+// WithStrictTraps causes the Mock to fail the test if a trap is closed without having matched any
+// calls. This catches dead test scaffolding, like a trap that silently stopped intercepting
+// anything after the code under test was refactored.
+func (m *Mock) WithStrictTraps() *Mock {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.strictTraps = true
+	return m
+}
+
+// TrapInfo describes an open Trap.
+type TrapInfo struct {
+	// Kind is the name of the trapped method, e.g. "NewTimer" or "Ticker.Reset".
+	Kind string
+	// Tags are the tags the trap matches.
+	Tags     []string
+	Priority int
+	// Unreleased is the number of calls the trap has caught that are not yet released.
+	Unreleased int
+	// Matched is the number of calls the trap has matched.
+	Matched int
+}
+
+func (i TrapInfo) String() string {
+	return fmt.Sprintf("Trap %s(..., %v) priority %d, %d unreleased calls",
+		i.Kind, i.Tags, i.Priority, i.Unreleased)
+}
+
+// Traps returns descriptions of the Mock's open traps, in the order they were created. It is
+// useful for debugging, and for harness code to assert that expected traps are installed before
+// starting a workload.
+func (m *Mock) Traps() []TrapInfo {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	infos := make([]TrapInfo, 0, len(m.traps))
+	for _, t := range m.traps {
+		t.mu.Lock()
+		infos = append(infos, TrapInfo{
+			Kind:       t.fn.String(),
+			Tags:       t.tags,
+			Priority:   t.priority,
+			Unreleased: t.unreleasedCalls,
+			Matched:    t.matched,
+		})
+		t.mu.Unlock()
+	}
+	return infos
 }
 
 func (t *Trap) String() string {
 	return fmt.Sprintf("Trap %s(..., %v)", t.fn.String(), t.tags)
 }
 
-func (t *Trap) catch(c *apiCall) {
+// enqueue counts the call as queued on the trap, unless it will be released automatically or
+// passed to a handler, in which case it returns the handler. It is called synchronously as the
+// call is delivered, so that Len reflects the call as soon as it is made.
+func (t *Trap) enqueue(c *apiCall) func(*Call) {
+	if c.delayedReleases[t] != nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.handler == nil {
+		t.queued++
+	}
+	return t.handler
+}
+
+func (t *Trap) catch(c *apiCall, h func(*Call)) {
+	if dr := c.delayedReleases[t]; dr != nil {
+		t.releaseDelayed(c, dr)
+		return
+	}
+	if h != nil {
+		call := t.accept(c)
+		hs := &handling{active: true}
+		call.handling = hs
+		h(call)
+		hs.mu.Lock()
+		hs.active = false
+		released := hs.released
+		hs.mu.Unlock()
+		if released {
+			c.releases.Done()
+		}
+		return
+	}
 	select {
 	case t.calls <- c:
 	case <-t.done:
+		t.mu.Lock()
+		t.queued--
+		t.mu.Unlock()
 		c.releases.Done()
 	}
 }
@@ -754,8 +2732,16 @@ func (t *Trap) matches(c *apiCall) bool {
 	if t.fn != c.fn {
 		return false
 	}
-	for _, tag := range t.tags {
-		if !slices.Contains(c.Tags, tag) {
+	if t.matchD != nil && !t.matchD(c.Duration) {
+		return false
+	}
+	return containsAll(c.Tags, t.tags)
+}
+
+// containsAll returns whether tags contains every tag in want.
+func containsAll(tags, want []string) bool {
+	for _, tag := range want {
+		if !slices.Contains(tags, tag) {
 			return false
 		}
 	}
@@ -771,9 +2757,21 @@ func (t *Trap) Close() {
 		return // already closed
 	default:
 	}
-	if t.unreleasedCalls != 0 {
+	t.mock.tb.Helper()
+	t.closeLocked()
+}
+
+func (t *Trap) closeLocked() {
+	t.mu.Lock()
+	unreleased := t.unreleasedCalls
+	t.mu.Unlock()
+	if unreleased != 0 {
+		t.mock.tb.Helper()
+		t.mock.tb.Errorf("%s Closed() with %d unreleased calls", t, unreleased)
+	}
+	if t.mock.strictTraps && t.matched == 0 {
 		t.mock.tb.Helper()
-		t.mock.tb.Errorf("%s Closed() with %d unreleased calls", t, t.unreleasedCalls)
+		t.mock.tb.Errorf("%s Closed() without matching any calls", t)
 	}
 	for i, tr := range t.mock.traps {
 		if t == tr {
@@ -798,19 +2796,164 @@ func (t *Trap) Wait(ctx context.Context) (*Call, error) {
 	case <-t.done:
 		return nil, ErrTrapClosed
 	case a := <-t.calls:
-		c := &Call{
-			Time:     a.Time,
-			Duration: a.Duration,
-			Tags:     a.Tags,
-			apiCall:  a,
-			trap:     t,
-			tb:       t.mock.tb,
+		return t.receive(a), nil
+	}
+}
+
+// TryWait returns the next call caught by the trap, if there is one, without blocking. Unlike Wait
+// with a short timeout, it does not race with calls that are being delivered, so it can be used to
+// assert that no call happened yet:
+//
+//	if c, ok := trap.TryWait(); ok {
+//		t.Fatalf("unexpected %s", c)
+//	}
+//
+// The call must be released as usual.
+func (t *Trap) TryWait() (*Call, bool) {
+	if t.Len() == 0 {
+		return nil, false
+	}
+	// a call is queued, so its delivery is imminent, unless the trap is closed.
+	select {
+	case <-t.done:
+		return nil, false
+	case a := <-t.calls:
+		return t.receive(a), true
+	}
+}
+
+// Len returns the number of calls the trap has caught that are waiting to be returned by Wait or
+// TryWait.
+func (t *Trap) Len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.queued
+}
+
+// receive accepts a call received from the calls channel, which is no longer queued.
+func (t *Trap) receive(a *apiCall) *Call {
+	t.mu.Lock()
+	t.queued--
+	t.mu.Unlock()
+	return t.accept(a)
+}
+
+// accept returns the Call for a caught call, which must then be released.
+func (t *Trap) accept(a *apiCall) *Call {
+	c := t.newCall(a)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.unreleasedCalls++
+	return c
+}
+
+func (t *Trap) newCall(a *apiCall) *Call {
+	return &Call{
+		Time:          a.Time,
+		Duration:      a.Duration,
+		Tags:          a.Tags,
+		VirtualTime:   a.virtualTime,
+		RealTime:      a.realTime,
+		apiCall:       a,
+		trap:          t,
+		tb:            t.mock.tb,
+		stageReleased: a.stageReleased,
+	}
+}
+
+// Notify returns a channel on which the trap delivers the calls it catches, as an alternative to
+// Wait that can be used in a select statement, or read later. Up to buffer calls are held until
+// they are read, in addition to the one waiting to be delivered. The calls must still be released,
+// and the channel is closed when the trap is. Calls that are never delivered because the trap is
+// closed are released.
+//
+// The buffer size is set by the first call to Notify; subsequent calls return the same channel. A
+// trap's calls should be consumed either with Notify or with Wait, not both.
+func (t *Trap) Notify(buffer int) <-chan *Call {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.notify == nil {
+		t.notify = make(chan *Call, buffer)
+		go t.forward(t.notify)
+	}
+	return t.notify
+}
+
+// forward delivers the calls caught by the trap to ch, until the trap is closed.
+func (t *Trap) forward(ch chan<- *Call) {
+	defer close(ch)
+	for {
+		c, err := t.Wait(context.Background())
+		if err != nil {
+			return
 		}
-		t.mu.Lock()
-		defer t.mu.Unlock()
-		t.unreleasedCalls++
-		return c, nil
+		select {
+		case ch <- c:
+		case <-t.done:
+			_ = c.Release(context.Background())
+			return
+		}
+	}
+}
+
+// Handle causes the trap to pass the calls it catches to h, rather than delivering them to Wait. h
+// runs on a goroutine of the Mock, while the call is blocked, and should inspect and release it
+// inline. Releasing the call inside h returns immediately, and the call completes once h returns:
+//
+//	trap := mClock.Trap().NewTimer("retry").Handle(func(c *quartz.Call) {
+//		// assert on c.Duration
+//		c.MustRelease(ctx)
+//	})
+//
+// Each trap that catches a call runs its handler on its own goroutine, so calls caught by several
+// traps can be released without the test juggling goroutines. If h does not release the call, it
+// must arrange for it to be released later. Handle returns the trap, and should be called before
+// any calls are caught.
+func (t *Trap) Handle(h func(c *Call)) *Trap {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.handler = h
+	return t
+}
+
+// ExpectNone advances the clock by within, as if by AdvanceTo, and fails the test if the trap catches
+// any calls in the meantime, reporting each call and the virtual time it happened at. The calls are
+// released, so that the advance can complete. It returns whether no calls were caught.
+//
+// The trap must not be waited on by anything else while ExpectNone runs.
+func (t *Trap) ExpectNone(ctx context.Context, within time.Duration) bool {
+	m := t.mock
+	m.tb.Helper()
+	waitCtx, cancel := context.WithCancel(ctx)
+	var unexpected []string
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			c, err := t.Wait(waitCtx)
+			if err != nil {
+				return
+			}
+			m.mu.Lock()
+			at := m.cur
+			m.mu.Unlock()
+			unexpected = append(unexpected, fmt.Sprintf("%s at %s", c, at.Format(time.RFC3339Nano)))
+			_ = c.Release(ctx)
+		}
+	}()
+	m.mu.Lock()
+	target := addDuration(m.cur, within)
+	m.mu.Unlock()
+	err := m.AdvanceTo(target).Wait(ctx)
+	cancel()
+	<-done
+	if err != nil {
+		m.tb.Errorf("%s ExpectNone: %s", t, err)
+	}
+	for _, u := range unexpected {
+		m.tb.Errorf("%s ExpectNone: unexpected call within %s: %s", t, within, u)
 	}
+	return err == nil && len(unexpected) == 0
 }
 
 // MustWait calls Wait() and then if there is an error, immediately fails the
@@ -3,11 +3,19 @@ package quartz_test
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
+	"math"
 	"os"
+	"reflect"
 	"runtime/pprof"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -325,187 +333,2668 @@ func TestTickerFunc_LongCallback(t *testing.T) {
 	w.MustWait(testCtx)
 }
 
-func Test_MultipleTraps(t *testing.T) {
+func TestTickerFunc_Stop(t *testing.T) {
 	t.Parallel()
 	testCtx, testCancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer testCancel()
 	mClock := quartz.NewMock(t)
 
-	trap0 := mClock.Trap().Now("0")
-	defer trap0.Close()
-	trap1 := mClock.Trap().Now("1")
-	defer trap1.Close()
+	trap := mClock.Trap().TickerFuncStop("loop")
+	defer trap.Close()
 
-	timeCh := make(chan time.Time)
+	ticks := 0
+	tkr := mClock.TickerFunc(testCtx, time.Second, func() error {
+		ticks++
+		return nil
+	}, "loop")
+	mClock.Advance(time.Second).MustWait(testCtx)
+
+	go tkr.Stop("loop")
+	trap.MustWait(testCtx).MustRelease(testCtx)
+	if err := tkr.Wait(); !errors.Is(err, quartz.ErrStopped) {
+		t.Fatalf("expected ErrStopped, got %v", err)
+	}
+	if _, ok := mClock.Peek(); ok {
+		t.Fatal("expected no pending events")
+	}
+	if ticks != 1 {
+		t.Fatalf("expected 1 tick, got %d", ticks)
+	}
+}
+
+func TestAdvanceUntil(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mClock := quartz.NewMock(t)
+	start := mClock.Now()
+	var attempts atomic.Int64
+	w := mClock.AdaptiveTickerFunc(ctx, func(n int, _ error) time.Duration {
+		return time.Duration(1<<n) * time.Second
+	}, func() error {
+		attempts.Add(1)
+		return nil
+	})
+	defer w.Stop()
+	err := mClock.AdvanceUntil(ctx, func() bool { return attempts.Load() == 3 }, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 1s + 2s + 4s
+	if got := mClock.Since(start); got != 7*time.Second {
+		t.Fatalf("expected to advance 7s, got %s", got)
+	}
+
+	err = mClock.AdvanceUntil(ctx, func() bool { return attempts.Load() == 10 }, time.Minute)
+	if !errors.Is(err, quartz.ErrConditionNotMet) {
+		t.Fatalf("expected ErrConditionNotMet, got %v", err)
+	}
+	// 8s + 16s + 32s, but not the 64s that would exceed the budget
+	if got := mClock.Since(start); got != 63*time.Second {
+		t.Fatalf("expected to advance 63s, got %s", got)
+	}
+}
+
+func TestRunToIdle(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mClock := quartz.NewMock(t)
+	var retries int
+	var retry func()
+	retry = func() {
+		retries++
+		if retries < 4 {
+			mClock.AfterFunc(time.Duration(retries)*time.Second, retry, "retry")
+		}
+	}
+	mClock.AfterFunc(time.Second, retry, "retry")
+	mClock.AfterFunc(time.Minute, func() {}, "cleanup")
+	d, err := mClock.RunToIdle(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d != time.Minute || retries != 4 {
+		t.Fatalf("expected to advance 1m with 4 retries, got %s with %d", d, retries)
+	}
+
+	mClock.NewTicker(time.Hour)
+	if _, err := mClock.RunToIdle(ctx); err == nil || !strings.Contains(err.Error(), "NewTicker") {
+		t.Fatalf("expected error mentioning the ticker, got %v", err)
+	}
+}
+
+func TestAdvanceNextN(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mClock := quartz.NewMock(t)
+	var calls atomic.Int64
+	mClock.AfterFunc(time.Second, func() { calls.Add(1) }, "a")
+	mClock.AfterFunc(3*time.Second, func() { calls.Add(1) }, "b")
+	mClock.AfterFunc(3*time.Second, func() { calls.Add(1) }, "c")
+	mClock.AfterFunc(time.Hour, func() { calls.Add(1) }, "d")
+
+	d, events, err := mClock.AdvanceNextN(ctx, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d != 3*time.Second {
+		t.Fatalf("expected 3s, got %s", d)
+	}
+	if n := calls.Load(); n != 3 {
+		t.Fatalf("expected 3 calls, got %d", n)
+	}
+	if len(events) != 3 || events[0].Tags[0] != "a" {
+		t.Fatalf("unexpected events %v", events)
+	}
+	for _, e := range events[1:] {
+		if e.Deadline.Sub(events[0].Deadline) != 2*time.Second {
+			t.Fatalf("unexpected event %v", e)
+		}
+	}
+}
+
+func TestEvents(t *testing.T) {
+	t.Parallel()
+
+	mClock := quartz.NewMock(t)
+	start := mClock.Now()
+	tkr := mClock.NewTicker(time.Minute, "poll")
+	defer tkr.Stop()
+	mClock.AfterFunc(time.Hour, func() {}, "expire")
+	mClock.NewTimer(time.Second, "retry")
+
+	events := mClock.Events()
+	want := []quartz.EventInfo{
+		{Kind: "NewTimer", Tags: []string{"retry"}, Deadline: start.Add(time.Second), Duration: time.Second},
+		{Kind: "NewTicker", Tags: []string{"poll"}, Deadline: start.Add(time.Minute), Duration: time.Minute},
+		{Kind: "AfterFunc", Tags: []string{"expire"}, Deadline: start.Add(time.Hour), Duration: time.Hour},
+	}
+	if !reflect.DeepEqual(events, want) {
+		t.Fatalf("expected %v, got %v", want, events)
+	}
+}
+
+func TestAdvanceNextEvents(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mClock := quartz.NewMock(t)
+	tkr := mClock.NewTicker(time.Second, "poll")
+	defer tkr.Stop()
+	mClock.AfterFunc(3*time.Second, func() {}, "shutdown")
+
+	ticks := 0
+	for {
+		_, events, w := mClock.AdvanceNextEvents()
+		w.MustWait(ctx)
+		if slices.ContainsFunc(events, func(e quartz.EventInfo) bool { return e.Tags[0] == "shutdown" }) {
+			break
+		}
+		<-tkr.C
+		ticks++
+	}
+	if ticks != 2 {
+		t.Fatalf("expected 2 ticks before shutdown, got %d", ticks)
+	}
+}
+
+func TestAdvanceNextWithin(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mClock := quartz.NewMock(t)
+	fired := make(chan struct{})
+	mClock.AfterFunc(90*time.Second, func() { close(fired) })
+
+	d, w, ok := mClock.AdvanceNextWithin(time.Minute)
+	w.MustWait(ctx)
+	if ok || d != time.Minute {
+		t.Fatalf("expected to advance 1m without firing, got %s, %t", d, ok)
+	}
+	d, w, ok = mClock.AdvanceNextWithin(time.Minute)
+	w.MustWait(ctx)
+	if !ok || d != 30*time.Second {
+		t.Fatalf("expected to advance 30s to the event, got %s, %t", d, ok)
+	}
+	<-fired
+}
+
+func TestAdvanceNextWithin_FailOnIdleAdvance(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// with nothing scheduled, AdvanceNextWithin advances by the limit without failing the test.
+	mClock := quartz.NewMock(t).WithFailOnIdleAdvance()
+	start := mClock.Now()
+	d, w, ok := mClock.AdvanceNextWithin(time.Minute)
+	w.MustWait(ctx)
+	if ok || d != time.Minute {
+		t.Fatalf("expected to advance 1m without firing, got %s, %t", d, ok)
+	}
+	if got := mClock.Since(start); got != time.Minute {
+		t.Fatalf("expected the clock to advance 1m, got %s", got)
+	}
+}
+
+func TestSleep(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mClock := quartz.NewMock(t)
+	trap := mClock.Trap().Sleep("backoff")
+	defer trap.Close()
+
+	done := make(chan struct{})
 	go func() {
-		timeCh <- mClock.Now("0", "1")
+		defer close(done)
+		mClock.Sleep(5*time.Second, "backoff")
 	}()
+	c := trap.MustWait(ctx)
+	if c.Duration != 5*time.Second {
+		t.Fatalf("expected 5s, got %s", c.Duration)
+	}
+	c.MustRelease(ctx)
 
-	c0 := trap0.MustWait(testCtx)
-	mClock.Advance(time.Second)
-	// the two trapped call instances need to be released on separate goroutines since they each wait for the Now() call
-	// to return, which is blocked on both releases happening. If you release them on the same goroutine, in either
-	// order, it will deadlock.
+	mClock.Advance(4 * time.Second).MustWait(ctx)
+	select {
+	case <-done:
+		t.Fatal("Sleep returned early")
+	default:
+	}
+	d, w := mClock.AdvanceNext()
+	w.MustWait(ctx)
+	if d != time.Second {
+		t.Fatalf("expected to advance 1s, got %s", d)
+	}
+	select {
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for Sleep to return")
+	case <-done:
+	}
+
+	// non-positive durations return immediately
+	mClock.Sleep(0)
+}
+
+func TestPeekN(t *testing.T) {
+	t.Parallel()
+
+	mClock := quartz.NewMock(t)
+	start := mClock.Now()
+	if got := mClock.PeekN(3); len(got) != 0 {
+		t.Fatalf("expected no events, got %v", got)
+	}
+	mClock.NewTimer(4*time.Second, "retry", "3")
+	mClock.NewTimer(time.Second, "retry", "1")
+	mClock.NewTimer(2*time.Second, "retry", "2")
+	mClock.NewTicker(10 * time.Second)
+
+	got := mClock.PeekN(3)
+	if len(got) != 3 {
+		t.Fatalf("expected 3 events, got %v", got)
+	}
+	for i, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second} {
+		if d := got[i].Deadline.Sub(start); d != want {
+			t.Fatalf("expected event %d in %s, got %s", i, want, d)
+		}
+		if got[i].Tags[1] != strconv.Itoa(i+1) {
+			t.Fatalf("unexpected tags %v", got[i].Tags)
+		}
+	}
+	if got := mClock.PeekN(10); len(got) != 4 || got[3].Kind != "NewTicker" {
+		t.Fatalf("expected 4 events, ending with the ticker, got %v", got)
+	}
+}
+
+func TestSleepContext(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mClock := quartz.NewMock(t)
+	trap := mClock.Trap().SleepContext()
+	defer trap.Close()
+	errs := make(chan error, 1)
+	go func() {
+		errs <- mClock.SleepContext(ctx, time.Second)
+	}()
+	c := trap.MustWait(ctx)
+	if c.Duration != time.Second {
+		t.Fatalf("expected 1s, got %s", c.Duration)
+	}
+	c.MustRelease(ctx)
+	mClock.Advance(time.Second).MustWait(ctx)
+	if err := <-errs; err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	sCtx, sCancel := context.WithCancel(ctx)
+	go func() {
+		errs <- mClock.SleepContext(sCtx, time.Minute)
+	}()
+	trap.MustWait(ctx).MustRelease(ctx)
+	sCancel()
+	if err := <-errs; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if _, ok := mClock.Peek(); ok {
+		t.Fatal("expected canceled sleep to be removed from the schedule")
+	}
+}
+
+func TestWithTimeout(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mClock := quartz.NewMock(t)
+	start := mClock.Now()
+	trap := mClock.Trap().WithTimeout("req")
+	defer trap.Close()
+
+	ctxs := make(chan context.Context)
+	go func() {
+		tCtx, tCancel := mClock.WithTimeout(ctx, time.Minute, "req")
+		t.Cleanup(tCancel)
+		ctxs <- tCtx
+	}()
+	c := trap.MustWait(ctx)
+	if c.Duration != time.Minute {
+		t.Fatalf("expected 1m, got %s", c.Duration)
+	}
+	c.MustRelease(ctx)
+	tCtx := <-ctxs
+	if dl, ok := tCtx.Deadline(); !ok || !dl.Equal(start.Add(time.Minute)) {
+		t.Fatalf("expected virtual deadline, got %s, %t", dl, ok)
+	}
+	if d, ok := mClock.Peek(); !ok || d != time.Minute {
+		t.Fatalf("expected deadline event in 1m, got %s, %t", d, ok)
+	}
+	// contexts derived from it inherit the deadline error.
+	child, childCancel := context.WithCancel(tCtx)
+	defer childCancel()
+	mClock.Advance(59 * time.Second).MustWait(ctx)
+	if err := tCtx.Err(); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	mClock.Advance(time.Second).MustWait(ctx)
+	if err := tCtx.Err(); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+	<-child.Done()
+	if err := child.Err(); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected derived context.DeadlineExceeded, got %v", err)
+	}
+
+	// canceling removes the deadline from the schedule
+	dCtx, dCancel := mClock.WithDeadline(ctx, mClock.Now().Add(time.Hour))
+	dCancel()
+	if err := dCtx.Err(); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if _, ok := mClock.Peek(); ok {
+		t.Fatal("expected no events")
+	}
+
+	// canceling the parent cancels the context.
+	parent, parentCancel := context.WithCancel(ctx)
+	cCtx, cCancel := mClock.WithDeadline(parent, mClock.Now().Add(time.Hour))
+	defer cCancel()
+	parentCancel()
+	<-cCtx.Done()
+	if err := cCtx.Err(); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	// the deadline is the earlier of the parent's and the virtual one.
+	early := start.Add(-time.Hour)
+	eParent, eParentCancel := context.WithDeadline(ctx, early)
+	defer eParentCancel()
+	eCtx, eCancel := mClock.WithTimeout(eParent, time.Hour)
+	defer eCancel()
+	if dl, _ := eCtx.Deadline(); !dl.Equal(early) {
+		t.Fatalf("expected parent deadline %s, got %s", early, dl)
+	}
+
+	// past deadlines are exceeded immediately
+	pCtx, pCancel := mClock.WithDeadline(ctx, start)
+	defer pCancel()
+	if err := pCtx.Err(); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestSet_FiresIntermediateEvents(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mClock := quartz.NewMock(t)
+	mClock.Set(time.Date(2024, 1, 31, 22, 0, 0, 0, time.UTC)).MustWait(ctx)
+	var fired []time.Time
+	mClock.TickerFunc(ctx, time.Hour, func() error {
+		fired = append(fired, mClock.Now())
+		return nil
+	})
+	midnight := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	mClock.Set(midnight.Add(time.Minute)).MustWait(ctx)
+	if !mClock.Now().Equal(midnight.Add(time.Minute)) {
+		t.Fatalf("expected time %s, got %s", midnight.Add(time.Minute), mClock.Now())
+	}
+	if len(fired) != 2 || !fired[0].Equal(midnight.Add(-time.Hour)) || !fired[1].Equal(midnight) {
+		t.Fatalf("expected ticks at 23:00 and midnight, got %v", fired)
+	}
+}
+
+func TestAdvanceTo(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// deliver each tick before firing the next, so that none are dropped
+	mClock := quartz.NewMock(t).WithSyncTicks("job")
+	start := mClock.Now()
+	tkr := mClock.NewTicker(time.Minute, "job")
+	var ticks []time.Time
 	done := make(chan struct{})
 	go func() {
 		defer close(done)
-		c0.MustRelease(testCtx)
+		for i := 0; i < 3; i++ {
+			ticks = append(ticks, <-tkr.C)
+		}
 	}()
-	c1 := trap1.MustWait(testCtx)
-	mClock.Advance(time.Second)
-	c1.MustRelease(testCtx)
+	mClock.AdvanceTo(start.Add(3*time.Minute + 30*time.Second)).MustWait(ctx)
+	<-done
+	for i, tick := range ticks {
+		if want := start.Add(time.Duration(i+1) * time.Minute); !tick.Equal(want) {
+			t.Fatalf("expected tick %d at %s, got %s", i, want, tick)
+		}
+	}
+	if d, ok := mClock.Peek(); !ok || d != 30*time.Second {
+		t.Fatalf("expected next tick in 30s, got %s", d)
+	}
+
+	tRunFail(t, func(tb testing.TB) {
+		mClock := quartz.NewMock(tb)
+		mClock.AdvanceTo(mClock.Now().Add(-time.Second))
+	})
+}
+
+func TestJump(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mClock := quartz.NewMock(t)
+	start := mClock.Now()
+	var fired []time.Time
+	mClock.TickerFunc(ctx, time.Second, func() error {
+		fired = append(fired, mClock.Now())
+		return nil
+	})
+	tmr := mClock.NewTimer(time.Minute)
+	target := start.Add(24 * time.Hour)
+	mClock.Jump(target).MustWait(ctx)
+	if len(fired) != 1 || !fired[0].Equal(target) {
+		t.Fatalf("expected a single late tick at %s, got %v", target, fired)
+	}
+	select {
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for timer")
+	case tme := <-tmr.C:
+		if !tme.Equal(target) {
+			t.Fatalf("expected the timer to fire late at %s, got %s", target, tme)
+		}
+	}
+	if d, ok := mClock.Peek(); !ok || d != time.Second {
+		t.Fatalf("expected next tick in 1s, got %s", d)
+	}
 
+	tRunFail(t, func(tb testing.TB) {
+		mClock := quartz.NewMock(tb)
+		mClock.Jump(mClock.Now().Add(-time.Second))
+	})
+}
+
+func TestAutoAdvance(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mClock := quartz.NewMock(t)
+	start := mClock.Now()
+	trap := mClock.Trap().AdvanceNext()
+	defer trap.Close()
+	done := make(chan []time.Time)
+	go func() {
+		var seen []time.Time
+		for i := 0; i < 3; i++ {
+			seen = append(seen, <-mClock.After(time.Duration(i+1)*time.Second))
+		}
+		done <- seen
+	}()
+	stop := mClock.AutoAdvance(ctx, 5*time.Millisecond)
+	defer stop()
+	// each step is an AdvanceNext.
+	for i := 0; i < 3; i++ {
+		trap.MustWait(ctx).MustRelease(ctx)
+	}
+	var seen []time.Time
 	select {
-	case <-done:
-	case <-testCtx.Done():
-		t.Fatal("timed out waiting for c0.Release()")
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for auto-advance")
+	case seen = <-done:
+	}
+	for i, want := range []time.Duration{time.Second, 3 * time.Second, 6 * time.Second} {
+		if !seen[i].Equal(start.Add(want)) {
+			t.Fatalf("expected time %d to be %s, got %s", i, start.Add(want), seen[i])
+		}
+	}
+
+	// stopping waits for the step in progress, and no step is in progress once stopped.
+	mClock.NewTimer(time.Second)
+	c := trap.MustWait(ctx)
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		stop()
+	}()
+	c.MustRelease(ctx)
+	select {
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for stop")
+	case <-stopped:
+	}
+	if mClock.Advancing() {
+		t.Fatal("expected no advance in progress after stop")
+	}
+	if !mClock.Now().Equal(start.Add(7 * time.Second)) {
+		t.Fatalf("expected the clock to stop at %s, got %s", start.Add(7*time.Second), mClock.Now())
+	}
+}
+
+func TestBreakpoint(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mClock := quartz.NewMock(t)
+	start := mClock.Now()
+	var ticks atomic.Int64
+	mClock.TickerFunc(ctx, time.Minute, func() error {
+		ticks.Add(1)
+		return nil
+	})
+	bp := mClock.Breakpoint(start.Add(90*time.Minute + 30*time.Second))
+	w := mClock.AdvanceTo(start.Add(3 * time.Hour))
+	bp.MustWait(ctx)
+	if got := mClock.Now(); !got.Equal(start.Add(90*time.Minute + 30*time.Second)) {
+		t.Fatalf("expected to pause at 1h30m30s, got %s", got.Sub(start))
+	}
+	if n := ticks.Load(); n != 90 {
+		t.Fatalf("expected 90 ticks before the breakpoint, got %d", n)
+	}
+	if strings.Contains(w.String(), "done") {
+		t.Fatal("expected advance to be paused at the breakpoint")
+	}
+	bp.Resume()
+	w.MustWait(ctx)
+	if n := ticks.Load(); n != 180 {
+		t.Fatalf("expected 180 ticks, got %d", n)
+	}
+}
+
+func TestFrozen(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mClock := quartz.NewMock(t)
+	start := mClock.Now()
+	var inCallback time.Time
+	mClock.AfterFunc(time.Second, func() { inCallback = mClock.Now() })
+	mClock.Frozen(func() {
+		mClock.Advance(time.Second).MustWait(ctx)
+		if !inCallback.Equal(start) {
+			t.Fatalf("expected callback to see frozen time %s, got %s", start, inCallback)
+		}
+		mClock.Frozen(func() {
+			if got := mClock.Since(start); got != 0 {
+				t.Fatalf("expected no time to pass, got %s", got)
+			}
+		})
+		if got := mClock.Now(); !got.Equal(start) {
+			t.Fatalf("expected frozen time %s, got %s", start, got)
+		}
+	})
+	if got := mClock.Now(); !got.Equal(start.Add(time.Second)) {
+		t.Fatalf("expected time %s after unfreezing, got %s", start.Add(time.Second), got)
+	}
+}
+
+func TestWithStartTime(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone data not available: %s", err)
+	}
+	// just before clocks go forward
+	start := time.Date(2024, 3, 10, 1, 59, 0, 0, ny)
+	mClock := quartz.NewMock(t).WithStartTime(start)
+	if got := mClock.Now(); !got.Equal(start) {
+		t.Fatalf("expected %s, got %s", start, got)
+	}
+	mClock.NewTimer(time.Minute)
+	mClock.Advance(time.Minute).MustWait(ctx)
+	if got := mClock.Now().In(ny).Hour(); got != 3 {
+		t.Fatalf("expected 3am after the DST change, got %dh", got)
+	}
+
+	tRunFail(t, func(tb testing.TB) {
+		mClock := quartz.NewMock(tb)
+		mClock.NewTimer(time.Minute)
+		mClock.WithStartTime(start)
+	})
+}
+
+func TestWithMonotonicityCheck(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mClock := quartz.NewMock(t).WithMonotonicityCheck().WithAutoIncrement(time.Millisecond)
+	first := mClock.Now()
+	// explicitly moving the clock backwards is allowed
+	mClock.Set(first.Add(-time.Hour)).MustWait(ctx)
+	if got := mClock.Now(); !got.Equal(first.Add(-time.Hour)) {
+		t.Fatalf("expected %s, got %s", first.Add(-time.Hour), got)
+	}
+
+	tkr := mClock.NewTicker(time.Second)
+	defer tkr.Stop()
+	var last time.Time
+	for i := 0; i < 3; i++ {
+		mClock.AdvanceNext()
+		select {
+		case <-ctx.Done():
+			t.Fatal("timeout waiting for tick")
+		case last = <-tkr.C:
+		}
+		if now := mClock.Now(); now.Before(last) {
+			t.Fatalf("Now %s before tick %s", now, last)
+		}
+	}
+
+	// a timer created or reset with a negative duration delivers the current time.
+	tmr := mClock.NewTimer(-time.Minute)
+	if got := <-tmr.C; got.Before(last) {
+		t.Fatalf("expected immediate timer to deliver at least %s, got %s", last, got)
+	}
+	tmr.Reset(-time.Minute)
+	if got := <-tmr.C; got.Before(last) {
+		t.Fatalf("expected reset timer to deliver at least %s, got %s", last, got)
+	}
+}
+
+func TestWaitForEvents(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mClock := quartz.NewMock(t)
+	go func() {
+		mClock.NewTimer(time.Second, "other")
+		mClock.NewTimer(time.Second, "worker")
+		mClock.NewTicker(time.Minute, "worker")
+	}()
+	if err := mClock.WaitForEvents(ctx, 2, "worker"); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(mClock.Scheduled("worker")); got != 2 {
+		t.Fatalf("expected 2 worker events, got %d", got)
+	}
+
+	// events scheduled by the test don't count.
+	mClock.At(mClock.Now().Add(time.Hour), func() {}, "worker")
+	shortCtx, shortCancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer shortCancel()
+	if err := mClock.WaitForEvents(shortCtx, 3, "worker"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestBeforeAdvance(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mClock := quartz.NewMock(t)
+	start := mClock.Now()
+	var fired atomic.Bool
+	// schedule an event just in time for the advance to fire it.
+	mClock.BeforeAdvance(func(now time.Time) {
+		if now.Equal(start) {
+			mClock.AfterFunc(time.Second, func() { fired.Store(true) })
+		}
+	})
+	_, w := mClock.AdvanceNext()
+	w.MustWait(ctx)
+	if !fired.Load() {
+		t.Fatal("expected the event scheduled before advancing to fire")
+	}
+}
+
+func TestWaitForCall(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mClock := quartz.NewMock(t)
+	shortCtx, shortCancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer shortCancel()
+	if err := mClock.WaitForCall(shortCtx, "worker", 1); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		mClock.Now("other")
+		mClock.Now("worker", "poll")
+	}()
+	if err := mClock.WaitForCall(ctx, "poll", 1); err != nil {
+		t.Fatal(err)
+	}
+	// the call is not held, and waiting again for as many calls returns immediately.
+	<-done
+	if err := mClock.WaitForCall(ctx, "worker", 1); err != nil {
+		t.Fatal(err)
+	}
+
+	// waiting for the next call does not return for the earlier one.
+	n := mClock.CallCount("poll")
+	if n != 1 {
+		t.Fatalf("expected 1 call, got %d", n)
+	}
+	shortCtx, shortCancel = context.WithTimeout(ctx, 10*time.Millisecond)
+	defer shortCancel()
+	if err := mClock.WaitForCall(shortCtx, "poll", n+1); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+	go mClock.Now("poll")
+	if err := mClock.WaitForCall(ctx, "poll", n+1); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAt(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mClock := quartz.NewMock(t)
+	start := mClock.Now()
+	var order []string
+	var mu sync.Mutex
+	record := func(s string) func() {
+		return func() {
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, s)
+		}
+	}
+	mClock.AfterFunc(2*time.Second, record("timer"))
+	mClock.At(start.Add(time.Second), record("message"), "message")
+	mClock.At(start.Add(3*time.Second), record("config"), "config")
+
+	if got := mClock.PeekN(1); len(got) != 1 || got[0].Kind != "At" || got[0].Tags[0] != "message" {
+		t.Fatalf("expected message event first, got %v", got)
+	}
+	mClock.Advance(time.Second).MustWait(ctx)
+	if len(order) != 1 {
+		t.Fatalf("expected message to be delivered, got %v", order)
+	}
+	mClock.Advance(time.Second).MustWait(ctx)
+	mClock.Advance(time.Second).MustWait(ctx)
+	if strings.Join(order, ",") != "message,timer,config" {
+		t.Fatalf("unexpected order %v", order)
+	}
+	if _, ok := mClock.Peek(); ok {
+		t.Fatal("expected no events")
+	}
+
+	tRunFail(t, func(tb testing.TB) {
+		mClock := quartz.NewMock(tb)
+		mClock.At(mClock.Now(), func() {})
+	})
+}
+
+func TestAfter(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mClock := quartz.NewMock(t)
+	start := mClock.Now()
+	trap := mClock.Trap().After("retry")
+	defer trap.Close()
+
+	result := make(chan time.Time)
+	go func() {
+		select {
+		case tme := <-mClock.After(time.Minute, "retry"):
+			result <- tme
+		case <-ctx.Done():
+		}
+	}()
+	c := trap.MustWait(ctx)
+	if c.Duration != time.Minute {
+		t.Fatalf("expected 1m, got %s", c.Duration)
+	}
+	c.MustRelease(ctx)
+	mClock.Advance(time.Minute).MustWait(ctx)
+	select {
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for After")
+	case tme := <-result:
+		if !tme.Equal(start.Add(time.Minute)) {
+			t.Fatalf("expected time %v, got %v", start.Add(time.Minute), tme)
+		}
+	}
+}
+
+func TestNewTimerInto(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mClock := quartz.NewMock(t)
+	start := mClock.Now()
+	ch := make(chan time.Time)
+	recv := func(want time.Time) {
+		t.Helper()
+		select {
+		case <-ctx.Done():
+			t.Fatal("timeout waiting for delivery")
+		case got := <-ch:
+			if !got.Equal(want) {
+				t.Fatalf("expected time %v, got %v", want, got)
+			}
+		}
+	}
+	t1 := mClock.NewTimerInto(ch, time.Second)
+	if t1.C != nil {
+		t.Fatal("expected nil C")
+	}
+	mClock.NewTimerInto(ch, 2*time.Second)
+	t3 := mClock.NewTimerInto(ch, 3*time.Second)
+
+	mClock.Advance(time.Second).MustWait(ctx)
+	recv(start.Add(time.Second))
+
+	mClock.Advance(time.Second).MustWait(ctx)
+	recv(start.Add(2 * time.Second))
+	if !t3.Stop() {
+		t.Fatal("expected Stop to stop the third timer")
+	}
+	mClock.Advance(time.Second).MustWait(ctx)
+	select {
+	case got := <-ch:
+		t.Fatalf("unexpected delivery at %s", got)
+	default:
+	}
+	if t1.Reset(time.Second) {
+		t.Fatal("expected Reset on a fired timer to return false")
+	}
+	mClock.Advance(time.Second).MustWait(ctx)
+	recv(start.Add(4 * time.Second))
+
+	// a delivery that has not been read is abandoned when the timer is stopped
+	t1.Reset(time.Second)
+	mClock.Advance(time.Second).MustWait(ctx)
+	t1.Stop()
+	select {
+	case got := <-ch:
+		t.Fatalf("unexpected delivery at %s", got)
+	default:
+	}
+}
+
+func TestWithAfterFuncExecutor(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// queue callbacks, and run them on the test goroutine
+	queue := make(chan func(), 10)
+	mClock := quartz.NewMock(t).WithAfterFuncExecutor(func(fn func()) { queue <- fn })
+	var order []int
+	mClock.AfterFunc(2*time.Second, func() { order = append(order, 2) })
+	mClock.AfterFunc(time.Second, func() { order = append(order, 1) })
+	mClock.AfterFunc(2*time.Second, func() { order = append(order, 3) })
+
+	mClock.Advance(time.Second).MustWait(ctx)
+	if len(order) != 0 {
+		t.Fatalf("expected callbacks to be queued, got %v", order)
+	}
+	if len(queue) != 1 {
+		t.Fatalf("expected 1 queued callback, got %d", len(queue))
+	}
+	(<-queue)()
+	mClock.Advance(time.Second).MustWait(ctx)
+	if len(queue) != 2 {
+		t.Fatalf("expected 2 queued callbacks, got %d", len(queue))
+	}
+	(<-queue)()
+	(<-queue)()
+	if len(order) != 3 || order[0] != 1 {
+		t.Fatalf("unexpected callback order %v", order)
+	}
+}
+
+func TestAdaptiveTickerFunc(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mClock := quartz.NewMock(t)
+	trap := mClock.Trap().AdaptiveTickerFunc("poll")
+	defer trap.Close()
+
+	calls := 0
+	errFailed := errors.New("failed")
+	interval := func(n int, last error) time.Duration {
+		switch {
+		case n == 3:
+			return 0
+		case last != nil:
+			return time.Duration(1<<n) * time.Second
+		default:
+			return time.Second
+		}
+	}
+	var w quartz.StopWaiter
+	started := make(chan struct{})
+	go func() {
+		defer close(started)
+		w = mClock.AdaptiveTickerFunc(ctx, interval, func() error {
+			calls++
+			if calls < 3 {
+				return errFailed
+			}
+			return nil
+		}, "poll")
+	}()
+	c := trap.MustWait(ctx)
+	if c.Duration != time.Second {
+		t.Fatalf("expected initial interval 1s, got %s", c.Duration)
+	}
+	c.MustRelease(ctx)
+	<-started
+	// Subsequent intervals are computed, and trapped, while the tick is firing.
+	for _, want := range []time.Duration{2 * time.Second, 4 * time.Second} {
+		_, aw := mClock.AdvanceNext()
+		c := trap.MustWait(ctx)
+		if c.Duration != want {
+			t.Fatalf("expected interval %s, got %s", want, c.Duration)
+		}
+		c.MustRelease(ctx)
+		aw.MustWait(ctx)
+	}
+	// the last interval is non-positive, which stops the ticker
+	d, aw := mClock.AdvanceNext()
+	aw.MustWait(ctx)
+	if d != 4*time.Second {
+		t.Fatalf("expected to advance 4s, got %s", d)
+	}
+	if err := w.Wait(); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if calls != 3 {
+		t.Fatalf("expected 3 calls, got %d", calls)
+	}
+}
+
+func TestTimerFunc(t *testing.T) {
+	t.Parallel()
+	testCtx, testCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer testCancel()
+	mClock := quartz.NewMock(t)
+
+	trap := mClock.Trap().TimerFunc("timer")
+	defer trap.Close()
+
+	expectedErr := errors.New("callback error")
+	calls := 0
+	waiters := make(chan quartz.Waiter, 1)
+	go func() {
+		waiters <- mClock.TimerFunc(testCtx, time.Second, func() error {
+			calls++
+			return expectedErr
+		}, "timer")
+	}()
+	c := trap.MustWait(testCtx)
+	c.MustRelease(testCtx)
+	if c.Duration != time.Second {
+		t.Fatalf("expected 1s got %s", c.Duration)
+	}
+	w := <-waiters
+	mClock.Advance(time.Second).MustWait(testCtx)
+	if err := w.Wait(); !errors.Is(err, expectedErr) {
+		t.Fatalf("expected callback error, got %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected 1 call got %d", calls)
+	}
+	if _, ok := mClock.Peek(); ok {
+		t.Fatal("expected no pending events")
+	}
+
+	// canceling the context stops the timer
+	ctx, cancel := context.WithCancel(testCtx)
+	w = mClock.TimerFunc(ctx, time.Second, func() error {
+		t.Error("unexpected call")
+		return nil
+	})
+	cancel()
+	if err := w.Wait(); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if _, ok := mClock.Peek(); ok {
+		t.Fatal("expected no pending events")
+	}
+}
+
+func Test_MultipleTraps(t *testing.T) {
+	t.Parallel()
+	testCtx, testCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer testCancel()
+	mClock := quartz.NewMock(t)
+
+	trap0 := mClock.Trap().Now("0")
+	defer trap0.Close()
+	trap1 := mClock.Trap().Now("1")
+	defer trap1.Close()
+
+	timeCh := make(chan time.Time)
+	go func() {
+		timeCh <- mClock.Now("0", "1")
+	}()
+
+	c0 := trap0.MustWait(testCtx)
+	mClock.Advance(time.Second)
+	// the two trapped call instances need to be released on separate goroutines since they each wait for the Now() call
+	// to return, which is blocked on both releases happening. If you release them on the same goroutine, in either
+	// order, it will deadlock.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		c0.MustRelease(testCtx)
+	}()
+	c1 := trap1.MustWait(testCtx)
+	mClock.Advance(time.Second)
+	c1.MustRelease(testCtx)
+
+	select {
+	case <-done:
+	case <-testCtx.Done():
+		t.Fatal("timed out waiting for c0.Release()")
+	}
+
+	select {
+	case got := <-timeCh:
+		end := mClock.Now("end")
+		if !got.Equal(end) {
+			t.Fatalf("expected %s got %s", end, got)
+		}
+	case <-testCtx.Done():
+		t.Fatal("timed out waiting for Now()")
+	}
+}
+
+func TestTrap_Handle(t *testing.T) {
+	t.Parallel()
+	testCtx, testCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer testCancel()
+	mClock := quartz.NewMock(t)
+
+	var handled atomic.Int64
+	handler := func(c *quartz.Call) {
+		handled.Add(1)
+		c.MustRelease(testCtx)
+	}
+	trap0 := mClock.Trap().Now("0").Handle(handler)
+	defer trap0.Close()
+	trap1 := mClock.Trap().Now("1").Handle(handler)
+	defer trap1.Close()
+
+	// caught by both traps, and released inline by both handlers
+	mClock.Now("0", "1")
+	mClock.Now("1")
+	if n := handled.Load(); n != 3 {
+		t.Fatalf("expected 3 handled calls, got %d", n)
+	}
+}
+
+func TestTrap_TryWait(t *testing.T) {
+	t.Parallel()
+	testCtx, testCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer testCancel()
+	mClock := quartz.NewMock(t)
+
+	trap := mClock.Trap().Now()
+	defer trap.Close()
+	if c, ok := trap.TryWait(); ok {
+		t.Fatalf("unexpected call %s", c)
+	}
+	if n := trap.Len(); n != 0 {
+		t.Fatalf("expected no queued calls, got %d", n)
+	}
+
+	called := make(chan struct{})
+	go func() {
+		mClock.Now()
+		close(called)
+	}()
+	// wait for the call to reach the trap, before taking it with TryWait
+	for trap.Len() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	c, ok := trap.TryWait()
+	if !ok {
+		t.Fatal("expected a call")
+	}
+	if n := trap.Len(); n != 0 {
+		t.Fatalf("expected no queued calls, got %d", n)
+	}
+	c.MustRelease(testCtx)
+	<-called
+}
+
+func TestTrap_Advance(t *testing.T) {
+	t.Parallel()
+	testCtx, testCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer testCancel()
+	mClock := quartz.NewMock(t)
+	start := mClock.Now()
+
+	advanceTrap := mClock.Trap().Advance()
+	defer advanceTrap.Close()
+	nextTrap := mClock.Trap().AdvanceNext()
+	defer nextTrap.Close()
+	setTrap := mClock.Trap().Set()
+	defer setTrap.Close()
+
+	// a harness driving the clock
+	mClock.AfterFunc(time.Minute, func() {})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		mClock.Advance(time.Second).MustWait(testCtx)
+		_, w := mClock.AdvanceNext()
+		w.MustWait(testCtx)
+		mClock.Set(start.Add(time.Hour)).MustWait(testCtx)
+	}()
+
+	c := advanceTrap.MustWait(testCtx)
+	if c.Duration != time.Second {
+		t.Fatalf("expected Advance(1s), got %s", c)
+	}
+	if got := mClock.Now(); !got.Equal(start) {
+		t.Fatalf("expected the clock not to have advanced, got %s", got)
+	}
+	c.MustRelease(testCtx)
+	nextTrap.MustWait(testCtx).MustRelease(testCtx)
+	c = setTrap.MustWait(testCtx)
+	if !c.Time.Equal(start.Add(time.Hour)) {
+		t.Fatalf("expected Set(%s), got %s", start.Add(time.Hour), c)
+	}
+	c.MustRelease(testCtx)
+	<-done
+}
+
+func TestTrapGroup(t *testing.T) {
+	t.Parallel()
+	testCtx, testCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer testCancel()
+	mClock := quartz.NewMock(t)
+
+	timerTrap := mClock.Trap().NewTimer()
+	nowTrap := mClock.Trap().Now()
+	group := quartz.NewTrapGroup(timerTrap, nowTrap)
+
+	go mClock.Now()
+	c, trap := group.MustWait(testCtx)
+	if trap != nowTrap {
+		t.Fatalf("expected the Now trap, got %s", trap)
+	}
+	c.MustRelease(testCtx)
+
+	go mClock.NewTimer(time.Second)
+	c, trap = group.MustWait(testCtx)
+	if trap != timerTrap || c.Duration != time.Second {
+		t.Fatalf("expected NewTimer(1s) from the NewTimer trap, got %s from %s", c, trap)
+	}
+	c.MustRelease(testCtx)
+
+	// closed traps are skipped, until all are closed.
+	nowTrap.Close()
+	go mClock.NewTimer(2 * time.Second)
+	c, _ = group.MustWait(testCtx)
+	c.MustRelease(testCtx)
+	timerTrap.Close()
+	if _, _, err := group.Wait(testCtx); !errors.Is(err, quartz.ErrTrapClosed) {
+		t.Fatalf("expected ErrTrapClosed, got %v", err)
+	}
+}
+
+func Test_MultipleTrapsDeadlock(t *testing.T) {
+	t.Parallel()
+	tRunFail(t, func(t testing.TB) {
+		testCtx, testCancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer testCancel()
+		mClock := quartz.NewMock(t)
+
+		trap0 := mClock.Trap().Now("0")
+		defer trap0.Close()
+		trap1 := mClock.Trap().Now("1")
+		defer trap1.Close()
+
+		go func() {
+			mClock.Now("0", "1")
+		}()
+
+		c0 := trap0.MustWait(testCtx)
+		c0.MustRelease(testCtx) // deadlocks, test failure
+	})
+}
+
+func Test_TrapPriority(t *testing.T) {
+	t.Parallel()
+	testCtx, testCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer testCancel()
+	mClock := quartz.NewMock(t)
+
+	harness := mClock.Trap().WithPriority(-1).Now()
+	defer harness.Close()
+	test := mClock.Trap().Now("0")
+	defer test.Close()
+
+	timeCh := make(chan time.Time)
+	go func() {
+		timeCh <- mClock.Now("0")
+	}()
+
+	// with different priorities, both traps can be released from the same goroutine, highest
+	// priority first.
+	c := test.MustWait(testCtx)
+	mClock.Advance(time.Second).MustWait(testCtx)
+	c.MustRelease(testCtx)
+	c = harness.MustWait(testCtx)
+	mClock.Advance(time.Second).MustWait(testCtx)
+	c.MustRelease(testCtx)
+	harness.Close()
+
+	select {
+	case got := <-timeCh:
+		end := mClock.Now("end")
+		if !got.Equal(end) {
+			t.Fatalf("expected %s got %s", end, got)
+		}
+	case <-testCtx.Done():
+		t.Fatal("timed out waiting for Now()")
+	}
+}
+
+func TestCall_Record(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mClock := quartz.NewMock(t)
+	trap := mClock.Trap().NewTimer("worker")
+	defer trap.Close()
+	go mClock.NewTimer(90*time.Second, "worker", "retry")
+	c := trap.MustWait(ctx)
+	c.MustCancel(ctx)
+
+	b, err := json.Marshal(c.Record())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"version":      float64(quartz.CallRecordVersion),
+		"method":       "NewTimer",
+		"duration":     "1m30s",
+		"tags":         []any{"worker", "retry"},
+		"virtual_time": "2024-01-01T00:00:00Z",
+		"released":     true,
+		"canceled":     true,
+	}
+	delete(got, "real_time")
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestCall_TimeArgument(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mClock := quartz.NewMock(t)
+	start := mClock.Now()
+	sinceTrap := mClock.Trap().Since()
+	defer sinceTrap.Close()
+	untilTrap := mClock.Trap().Until()
+	defer untilTrap.Close()
+
+	ref := start.Add(-time.Minute)
+	go mClock.Since(ref)
+	c := sinceTrap.MustWait(ctx)
+	if !c.Time.Equal(ref) {
+		t.Fatalf("expected Since to be measured from %s, got %s", ref, c.Time)
+	}
+	c.MustRelease(ctx)
+
+	deadline := start.Add(time.Hour)
+	go mClock.Until(deadline)
+	c = untilTrap.MustWait(ctx)
+	if !c.Time.Equal(deadline) {
+		t.Fatalf("expected Until to be measured to %s, got %s", deadline, c.Time)
+	}
+	c.MustRelease(ctx)
+}
+
+func TestCall_ReleaseWith(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mClock := quartz.NewMock(t)
+	start := mClock.Now()
+	skewed := start.Add(-time.Hour)
+	trap := mClock.Trap().Now("cache")
+	defer trap.Close()
+
+	result := make(chan time.Time, 1)
+	go func() { result <- mClock.Now("cache") }()
+	trap.MustWait(ctx).MustReleaseWith(ctx, skewed)
+	if got := <-result; !got.Equal(skewed) {
+		t.Fatalf("expected %s, got %s", skewed, got)
+	}
+	if got := mClock.Now(); !got.Equal(start) {
+		t.Fatalf("expected the Mock to be unaffected at %s, got %s", start, got)
+	}
+
+	sinceTrap := mClock.Trap().Since()
+	defer sinceTrap.Close()
+	elapsed := make(chan time.Duration, 1)
+	go func() { elapsed <- mClock.Since(start) }()
+	sinceTrap.MustWait(ctx).MustReleaseWith(ctx, start.Add(time.Minute))
+	if got := <-elapsed; got != time.Minute {
+		t.Fatalf("expected 1m, got %s", got)
+	}
+
+	timerTrap := mClock.Trap().NewTimer()
+	defer timerTrap.Close()
+	go mClock.NewTimer(time.Second)
+	c := timerTrap.MustWait(ctx)
+	if err := c.ReleaseWith(ctx, start); !errors.Is(err, quartz.ErrReleaseWithNotSupported) {
+		t.Fatalf("expected ErrReleaseWithNotSupported, got %v", err)
+	}
+	c.MustRelease(ctx)
+}
+
+func TestCall_AdvanceThenRelease(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mClock := quartz.NewMock(t)
+	start := mClock.Now()
+	fired := make(chan struct{})
+	mClock.AfterFunc(50*time.Millisecond, func() { close(fired) })
+	trap := mClock.Trap().Now("request")
+	defer trap.Close()
+
+	result := make(chan time.Time, 1)
+	go func() { result <- mClock.Now("request") }()
+	trap.MustWait(ctx).MustAdvanceThenRelease(ctx, 100*time.Millisecond)
+	<-fired
+	if got := <-result; !got.Equal(start.Add(100 * time.Millisecond)) {
+		t.Fatalf("expected %s, got %s", start.Add(100*time.Millisecond), got)
+	}
+}
+
+func TestCall_Cancel(t *testing.T) {
+	t.Parallel()
+	testCtx, testCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer testCancel()
+	mClock := quartz.NewMock(t)
+
+	trap := mClock.Trap().AfterFunc()
+	defer trap.Close()
+	nowTrap := mClock.Trap().Now()
+	defer nowTrap.Close()
+
+	timers := make(chan *quartz.Timer, 1)
+	go func() {
+		timers <- mClock.AfterFunc(time.Second, func() {
+			t.Error("canceled AfterFunc called")
+		})
+	}()
+	trap.MustWait(testCtx).MustCancel(testCtx)
+	tmr := <-timers
+	if _, ok := mClock.Peek(); ok {
+		t.Fatal("expected no events scheduled")
+	}
+	if tmr.Stop() {
+		t.Fatal("expected canceled timer to be stopped")
+	}
+
+	go mClock.Now()
+	c := nowTrap.MustWait(testCtx)
+	if err := c.Cancel(testCtx); !errors.Is(err, quartz.ErrCancelNotSupported) {
+		t.Fatalf("expected ErrCancelNotSupported, got %v", err)
+	}
+	c.MustRelease(testCtx)
+}
+
+func TestCall_CancelAfter(t *testing.T) {
+	t.Parallel()
+	testCtx, testCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer testCancel()
+	mClock := quartz.NewMock(t)
+
+	trap := mClock.Trap().After("retry")
+	defer trap.Close()
+
+	chs := make(chan (<-chan time.Time), 1)
+	go func() {
+		chs <- mClock.After(time.Minute, "retry")
+	}()
+	trap.MustWait(testCtx).MustCancel(testCtx)
+	ch := <-chs
+	if _, ok := mClock.Peek(); ok {
+		t.Fatal("expected no events scheduled")
+	}
+	mClock.Advance(time.Minute).MustWait(testCtx)
+	select {
+	case tme := <-ch:
+		t.Fatalf("expected canceled After not to deliver, got %s", tme)
+	default:
+	}
+}
+
+func TestCall_WrapFunc(t *testing.T) {
+	t.Parallel()
+	testCtx, testCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer testCancel()
+	mClock := quartz.NewMock(t)
+
+	trap := mClock.Trap().AfterFunc()
+	defer trap.Close()
+
+	var calls []string
+	go mClock.AfterFunc(time.Second, func() {
+		calls = append(calls, "orig")
+	})
+	c := trap.MustWait(testCtx)
+	c.WrapFunc(func(orig func()) func() {
+		return func() {
+			calls = append(calls, "spy")
+			orig()
+		}
+	})
+	c.MustRelease(testCtx)
+	mClock.Advance(time.Second).MustWait(testCtx)
+	if len(calls) != 2 || calls[0] != "spy" || calls[1] != "orig" {
+		t.Fatalf("unexpected calls: %v", calls)
+	}
+}
+
+func TestTrapper_WithDurationMatcher(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mClock := quartz.NewMock(t)
+	trap := mClock.Trap().WithDurationMatcher(func(d time.Duration) bool {
+		return d == 30*time.Second
+	}).NewTimer()
+	defer trap.Close()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		// short timers flow through untrapped
+		mClock.NewTimer(time.Millisecond)
+		mClock.NewTimer(10 * time.Millisecond)
+		mClock.NewTimer(30 * time.Second)
+	}()
+	c := trap.MustWait(ctx)
+	if c.Duration != 30*time.Second {
+		t.Fatalf("expected 30s, got %s", c.Duration)
+	}
+	c.MustRelease(ctx)
+	<-done
+}
+
+func TestTrap_ExpectNone(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mClock := quartz.NewMock(t)
+	trap := mClock.Trap().NewTimer("retry")
+	defer trap.Close()
+	// the code under test retries after a minute
+	mClock.AfterFunc(time.Minute, func() {
+		mClock.NewTimer(time.Second, "retry")
+	})
+	if !trap.ExpectNone(ctx, 59*time.Second) {
+		t.Fatal("expected no calls within 59s")
+	}
+
+	tRunFail(t, func(tb testing.TB) {
+		mClock := quartz.NewMock(tb)
+		trap := mClock.Trap().NewTimer("retry")
+		defer trap.Close()
+		mClock.AfterFunc(time.Minute, func() {
+			mClock.NewTimer(time.Second, "retry")
+		})
+		trap.ExpectNone(ctx, time.Hour)
+	})
+}
+
+func TestTrap_ReleaseAfter(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mClock := quartz.NewMock(t)
+	start := mClock.Now()
+	trap := mClock.Trap().Now("slow").ReleaseAfter(10 * time.Millisecond)
+	defer trap.Close()
+
+	result := make(chan time.Time, 1)
+	go func() {
+		result <- mClock.Now("slow")
+	}()
+	if err := mClock.WaitForCall(ctx, "slow", 1); err != nil {
+		t.Fatal(err)
+	}
+	mClock.Advance(9 * time.Millisecond).MustWait(ctx)
+	select {
+	case <-result:
+		t.Fatal("Now returned early")
+	default:
+	}
+	mClock.Advance(time.Millisecond).MustWait(ctx)
+	// the AdvanceWaiter waits for the call to complete
+	select {
+	case got := <-result:
+		if !got.Equal(start.Add(10 * time.Millisecond)) {
+			t.Fatalf("expected %s, got %s", start.Add(10*time.Millisecond), got)
+		}
+	default:
+		t.Fatal("expected Now to have returned")
+	}
+
+	immediate := mClock.Trap().Since().ReleaseAfter(0)
+	defer immediate.Close()
+	if d := mClock.Since(start); d != 10*time.Millisecond {
+		t.Fatalf("expected 10ms, got %s", d)
+	}
+}
+
+func TestTrap_AutoRelease(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mClock := quartz.NewMock(t)
+	trap := mClock.Trap().NewTimer("retry").AutoRelease(2)
+	defer trap.Close()
+
+	mClock.NewTimer(time.Second, "retry")
+	mClock.NewTimer(2*time.Second, "retry")
+	calls := trap.AutoReleased()
+	if len(calls) != 2 {
+		t.Fatalf("expected 2 auto-released calls, got %d", len(calls))
+	}
+	for i, want := range []time.Duration{time.Second, 2 * time.Second} {
+		if calls[i].Duration != want {
+			t.Errorf("call %d: expected %s, got %s", i, want, calls[i].Duration)
+		}
+	}
+
+	// the third call is caught as usual
+	go mClock.NewTimer(3*time.Second, "retry")
+	c := trap.MustWait(ctx)
+	if c.Duration != 3*time.Second {
+		t.Fatalf("expected 3s, got %s", c.Duration)
+	}
+	c.MustRelease(ctx)
+}
+
+func TestTrap_CallTimes(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mClock := quartz.NewMock(t)
+	start := mClock.Now()
+	trap := mClock.Trap().NewTimer()
+	defer trap.Close()
+	mClock.Advance(time.Hour).MustWait(ctx)
+
+	before := time.Now()
+	go mClock.NewTimer(time.Second)
+	c := trap.MustWait(ctx)
+	c.MustRelease(ctx)
+	if !c.VirtualTime.Equal(start.Add(time.Hour)) {
+		t.Errorf("expected virtual time %s, got %s", start.Add(time.Hour), c.VirtualTime)
+	}
+	if c.RealTime.Before(before) || c.RealTime.After(time.Now()) {
+		t.Errorf("real time %s out of range", c.RealTime)
+	}
+}
+
+func TestTrap_Notify(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mClock := quartz.NewMock(t)
+	trap := mClock.Trap().Now("notify")
+	calls := trap.Notify(1)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		mClock.Now("notify", "first")
+		mClock.Now("notify", "second")
+	}()
+	for _, want := range []string{"first", "second"} {
+		select {
+		case c := <-calls:
+			if c.Tags[1] != want {
+				t.Fatalf("expected %s call, got %v", want, c.Tags)
+			}
+			c.MustRelease(ctx)
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for call")
+		}
+	}
+	<-done
+	trap.Close()
+	if _, ok := <-calls; ok {
+		t.Fatal("expected channel to be closed")
+	}
+}
+
+func TestTraps(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mClock := quartz.NewMock(t)
+	nowTrap := mClock.Trap().Now("now")
+	defer nowTrap.Close()
+	resetTrap := mClock.Trap().WithPriority(1).TickerReset()
+	defer resetTrap.Close()
+
+	go mClock.Now("now")
+	c := nowTrap.MustWait(ctx)
+	traps := mClock.Traps()
+	c.MustRelease(ctx)
+	if len(traps) != 2 {
+		t.Fatalf("expected 2 traps, got %v", traps)
+	}
+	if traps[0].Kind != "Now" || traps[0].Tags[0] != "now" || traps[0].Unreleased != 1 {
+		t.Fatalf("unexpected trap %s", traps[0])
+	}
+	if traps[1].Kind != "Ticker.Reset" || traps[1].Priority != 1 || traps[1].Unreleased != 0 {
+		t.Fatalf("unexpected trap %s", traps[1])
+	}
+
+	nowTrap.Close()
+	if traps := mClock.Traps(); len(traps) != 1 {
+		t.Fatalf("expected 1 trap, got %v", traps)
+	}
+}
+
+func TestCloseTraps(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mClock := quartz.NewMock(t).WithStrictTraps()
+	nowTrap := mClock.Trap().Now()
+	sinceTrap := mClock.Trap().Since()
+	go mClock.Now()
+	nowTrap.MustWait(ctx).MustRelease(ctx)
+	go mClock.Since(time.Time{})
+	sinceTrap.MustWait(ctx).MustRelease(ctx)
+
+	mClock.CloseTraps()
+	if traps := mClock.Traps(); len(traps) != 0 {
+		t.Fatalf("expected no traps, got %v", traps)
+	}
+	if _, err := nowTrap.Wait(ctx); !errors.Is(err, quartz.ErrTrapClosed) {
+		t.Fatalf("expected ErrTrapClosed, got %v", err)
+	}
+
+	tRunFail(t, func(t testing.TB) {
+		mClock := quartz.NewMock(t).WithStrictTraps()
+		mClock.Trap().Now("never")
+		mClock.Now("other")
+		mClock.CloseTraps()
+	})
+}
+
+func Test_UnreleasedCalls(t *testing.T) {
+	t.Parallel()
+	tRunFail(t, func(t testing.TB) {
+		testCtx, testCancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer testCancel()
+		mClock := quartz.NewMock(t)
+
+		trap := mClock.Trap().Now()
+		defer trap.Close()
+
+		go func() {
+			_ = mClock.Now()
+		}()
+
+		c := trap.MustWait(testCtx) // missing release
+		trap.Close()                // detect unreleased call and fail
+
+		c.Release(testCtx) // clean up goroutine
+	})
+}
+
+func Test_WithLogger(t *testing.T) {
+	t.Parallel()
+
+	tl := &testLogger{}
+	mClock := quartz.NewMock(t).WithLogger(tl)
+	mClock.Now("test", "Test_WithLogger")
+	if len(tl.calls) != 1 {
+		t.Fatalf("expected 1 call, got %d", len(tl.calls))
+	}
+	expectLogLine := "Mock Clock - [2024-01-01T00:00:00Z +0s] Now([test Test_WithLogger]) call, matched 0 traps"
+	if tl.calls[0] != expectLogLine {
+		t.Fatalf("expected log line %q, got %q", expectLogLine, tl.calls[0])
+	}
+
+	mClock.NewTimer(time.Second, "timer")
+	if len(tl.calls) != 2 {
+		t.Fatalf("expected 2 calls, got %d", len(tl.calls))
+	}
+	expectLogLine = "Mock Clock - [2024-01-01T00:00:00Z +0s] NewTimer(1s, [timer]) call, matched 0 traps"
+	if tl.calls[1] != expectLogLine {
+		t.Fatalf("expected log line %q, got %q", expectLogLine, tl.calls[1])
+	}
+
+	mClock.Advance(500 * time.Millisecond)
+	if len(tl.calls) != 3 {
+		t.Fatalf("expected 3 calls, got %d", len(tl.calls))
+	}
+	expectLogLine = "Mock Clock - [2024-01-01T00:00:00Z +0s] Advance(500ms)"
+	if tl.calls[2] != expectLogLine {
+		t.Fatalf("expected log line %q, got %q", expectLogLine, tl.calls[2])
+	}
+
+	mClock.Now()
+	if len(tl.calls) != 4 {
+		t.Fatalf("expected 4 calls, got %d", len(tl.calls))
+	}
+	expectLogLine = "Mock Clock - [2024-01-01T00:00:00.5Z +500ms] Now([]) call, matched 0 traps"
+	if tl.calls[3] != expectLogLine {
+		t.Fatalf("expected log line %q, got %q", expectLogLine, tl.calls[3])
+	}
+}
+
+type captureFailTB struct {
+	failed bool
+	testing.TB
+}
+
+func (t *captureFailTB) Errorf(format string, args ...any) {
+	t.Helper()
+	t.Logf(format, args...)
+	t.failed = true
+}
+
+func (t *captureFailTB) Error(args ...any) {
+	t.Helper()
+	t.Log(args...)
+	t.failed = true
+}
+
+func (t *captureFailTB) Fatal(args ...any) {
+	t.Helper()
+	t.Log(args...)
+	t.failed = true
+}
+
+func (t *captureFailTB) Fatalf(format string, args ...any) {
+	t.Helper()
+	t.Logf(format, args...)
+	t.failed = true
+}
+
+func (t *captureFailTB) Fail() {
+	t.failed = true
+}
+
+func (t *captureFailTB) FailNow() {
+	t.failed = true
+}
+
+func (t *captureFailTB) Failed() bool {
+	return t.failed
+}
+
+func tRunFail(t testing.TB, f func(t testing.TB)) {
+	tb := &captureFailTB{TB: t}
+	f(tb)
+	if !tb.Failed() {
+		t.Fatal("want test to fail")
+	}
+}
+
+func TestAssertNoClockCalls(t *testing.T) {
+	t.Parallel()
+
+	mClock := quartz.NewMock(t)
+	tmr := mClock.NewTimer(time.Second)
+	if !quartz.AssertNoClockCalls(t, mClock, func() {}) {
+		t.Fatal("expected no calls")
+	}
+	tRunFail(t, func(tb testing.TB) {
+		quartz.AssertNoClockCalls(tb, mClock, func() {
+			mClock.Now("hot")
+			tmr.Stop()
+		})
+	})
+	// calls after f returns are not counted.
+	mClock.Now()
+}
+
+func TestScope(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mClock := quartz.NewMock(t)
+	start := mClock.Now()
+	deadline := mClock.NewTimer(time.Hour)
+	defer deadline.Stop()
+
+	setup := mClock.Scope(10 * time.Minute)
+	setup.Advance(time.Minute).MustWait(ctx)
+	child := setup.Scope(5 * time.Minute)
+	child.AdvanceTo(start.Add(4 * time.Minute)).MustWait(ctx)
+	if r := child.Remaining(); r != 2*time.Minute {
+		t.Fatalf("expected 2m remaining in child, got %s", r)
+	}
+	if r := setup.Remaining(); r != 6*time.Minute {
+		t.Fatalf("expected 6m remaining in parent, got %s", r)
+	}
+
+	tRunFail(t, func(tb testing.TB) {
+		s := quartz.NewMock(tb).Scope(time.Minute)
+		s.Advance(2 * time.Minute).MustWait(ctx)
+	})
+	// the next event is beyond the budget, so the Scope doesn't advance to it.
+	tRunFail(t, func(tb testing.TB) {
+		m := quartz.NewMock(tb)
+		m.NewTimer(time.Hour)
+		m.Scope(time.Minute).AdvanceNext()
+		if !m.Now().Equal(start) {
+			t.Errorf("expected the clock not to move, got %s", m.Now())
+		}
+	})
+	if got := mClock.Since(start); got != 4*time.Minute {
+		t.Fatalf("expected 4m elapsed, got %s", got)
+	}
+}
+
+func TestSaveLoadSchedule(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	src := quartz.NewMock(t)
+	src.AfterFunc(time.Minute, func() {}, "session", "a")
+	src.AfterFunc(30*time.Second, func() {}, "session", "b")
+	tkr := src.NewTicker(time.Hour, "reaper")
+	defer tkr.Stop()
+	src.Advance(10 * time.Second).MustWait(ctx)
+	var buf bytes.Buffer
+	if err := src.SaveSchedule(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := quartz.NewMock(t)
+	var fired []string
+	err := quartz.LoadSchedule(&buf, func(e quartz.ScheduledEvent) error {
+		if e.Kind != "AfterFunc" {
+			return nil
+		}
+		tag := e.Tags[1]
+		dst.AfterFunc(e.In, func() { fired = append(fired, tag) }, e.Tags...)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	infos := dst.PeekN(2)
+	if len(infos) != 2 || infos[0].Duration != 20*time.Second || infos[1].Duration != 50*time.Second {
+		t.Fatalf("unexpected restored schedule %v", infos)
+	}
+	dst.AdvanceTo(dst.Now().Add(50 * time.Second)).MustWait(ctx)
+	if !slices.Equal(fired, []string{"b", "a"}) {
+		t.Fatalf("expected sessions b then a to fire, got %v", fired)
+	}
+}
+
+func TestExpect(t *testing.T) {
+	t.Parallel()
+
+	mClock := quartz.NewMock(t)
+	mClock.Expect().NewTimer(time.Minute, "reconnect").Times(3)
+	mClock.Expect().Now("never").Times(0)
+	mClock.Expect().TimerStop("reconnect")
+	for i := 0; i < 3; i++ {
+		mClock.NewTimer(time.Minute, "reconnect", "attempt")
+	}
+	mClock.NewTimer(time.Second, "reconnect").Stop("reconnect")
+	mClock.Expect().WithTimeout(time.Minute, "request")
+	mClock.Expect().TickerFuncStop("poll")
+	_, cancel := mClock.WithTimeout(context.Background(), time.Minute, "request")
+	defer cancel()
+	mClock.TickerFunc(context.Background(), time.Second, func() error { return nil }, "poll").Stop("poll")
+	mClock.AssertExpectations(t)
+
+	tRunFail(t, func(t testing.TB) {
+		mClock := quartz.NewMock(t)
+		mClock.Expect().NewTimer(time.Minute, "reconnect").Times(3)
+		mClock.NewTimer(time.Minute, "reconnect")
+		mClock.AssertExpectations(t)
+	})
+}
+
+func TestWithTagMapper(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mClock := quartz.NewMock(t).WithTagMapper(func(tags []string) []string {
+		return append(tags, "lib")
+	})
+	trap := mClock.Trap().NewTimer("lib")
+	defer trap.Close()
+
+	go mClock.NewTimer(time.Second, "retry")
+	c := trap.MustWait(ctx)
+	if !slices.Equal(c.Tags, []string{"retry", "lib"}) {
+		t.Fatalf("expected mapped tags, got %v", c.Tags)
+	}
+	c.MustRelease(ctx)
+	if events := mClock.PeekN(1); len(events) != 1 || !slices.Equal(events[0].Tags, []string{"retry", "lib"}) {
+		t.Fatalf("expected the timer to have the mapped tags, got %v", events)
+	}
+}
+
+var tagStrictLoop = quartz.RegisterTag("strict.loop")
+
+func TestWithStrictTags(t *testing.T) {
+	t.Parallel()
+
+	mClock := quartz.NewMock(t).WithStrictTags()
+	trap := mClock.Trap().NewTimer(tagStrictLoop).AutoRelease(1)
+	defer trap.Close()
+	mClock.NewTimer(time.Second, tagStrictLoop)
+
+	tRunFail(t, func(t testing.TB) {
+		mClock := quartz.NewMock(t).WithStrictTags()
+		mClock.Now("strict.lop")
+	})
+	tRunFail(t, func(t testing.TB) {
+		mClock := quartz.NewMock(t).WithStrictTags()
+		mClock.Trap().Now("strict.lop").Close()
+	})
+}
+
+func TestWithLoggerFor(t *testing.T) {
+	t.Parallel()
+
+	mainLog := &testLogger{}
+	syncLog := &testLogger{}
+	mClock := quartz.NewMock(t).WithLogger(mainLog).WithLoggerFor("sync", syncLog)
+	mClock.Now("sync", "poll")
+	mClock.Now("api")
+	mClock.Advance(time.Second)
+
+	if len(syncLog.calls) != 1 || !strings.Contains(syncLog.calls[0], "Now([sync poll])") {
+		t.Fatalf("expected the sync call to be logged to the sync logger, got %v", syncLog.calls)
+	}
+	if len(mainLog.calls) != 2 || !strings.Contains(mainLog.calls[0], "Now([api])") ||
+		!strings.Contains(mainLog.calls[1], "Advance(1s)") {
+		t.Fatalf("expected other messages to be logged to the main logger, got %v", mainLog.calls)
+	}
+}
+
+type testLogger struct {
+	calls []string
+}
+
+func (l *testLogger) Log(args ...any) {
+	l.calls = append(l.calls, fmt.Sprint(args...))
+}
+
+func (l *testLogger) Logf(format string, args ...any) {
+	l.calls = append(l.calls, fmt.Sprintf(format, args...))
+}
+
+func TestWithDropPolicy(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	tl := &testLogger{}
+	mClock := quartz.NewMock(t).WithLogger(tl).WithDropPolicy(quartz.DropLog)
+	tkr := mClock.NewTicker(time.Second, "tkr")
+	defer tkr.Stop()
+	for i := 0; i < 3; i++ {
+		mClock.Advance(time.Second).MustWait(ctx)
+	}
+	if n := mClock.DroppedTicks(); n != 2 {
+		t.Fatalf("expected 2 dropped ticks, got %d", n)
+	}
+	expectLogLine := "] dropped tick of NewTicker(1s, [tkr]); previous tick unread"
+	var dropLogs int
+	for _, c := range tl.calls {
+		if strings.HasSuffix(c, expectLogLine) {
+			dropLogs++
+		}
+	}
+	if dropLogs != 2 {
+		t.Fatalf("expected 2 log lines %q, got %v", expectLogLine, tl.calls)
+	}
+
+	<-tkr.C
+	mClock.Advance(time.Second).MustWait(ctx)
+	if n := mClock.DroppedTicks(); n != 2 {
+		t.Fatalf("expected 2 dropped ticks, got %d", n)
+	}
+}
+
+func TestExtremeDurations(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mClock := quartz.NewMock(t)
+	mClock.Set(time.Date(9999, 1, 1, 0, 0, 0, 0, time.UTC)).MustWait(ctx)
+	mClock.NewTimer(math.MaxInt64, "huge")
+	if d, ok := mClock.Peek(); !ok || d != math.MaxInt64 {
+		t.Fatalf("expected Peek to return %s, got %s", time.Duration(math.MaxInt64), d)
+	}
+	mClock.Advance(time.Hour).MustWait(ctx)
+	if d, _ := mClock.Peek(); d != math.MaxInt64-time.Hour {
+		t.Fatalf("expected Peek to return %s, got %s", math.MaxInt64-time.Hour, d)
+	}
+
+	// Deadlines that would overflow time.Time are clamped, so the timer never fires.
+	mClock = quartz.NewMock(t)
+	mClock.Set(time.Unix(1<<63-62135596801-2*3600, 0)).MustWait(ctx)
+	fired := false
+	mClock.AfterFunc(math.MaxInt64, func() { fired = true }, "overflow")
+	if d, ok := mClock.Peek(); !ok || d <= 0 || d > 3*time.Hour {
+		t.Fatalf("expected timer at the end of time, got %s", d)
+	}
+	mClock.Advance(time.Hour).MustWait(ctx)
+	if fired {
+		t.Fatal("expected timer not to fire")
+	}
+}
+
+func TestWithMaxDuration(t *testing.T) {
+	t.Parallel()
+	mClock := quartz.NewMock(t).WithMaxDuration(24 * time.Hour)
+	mClock.NewTimer(24*time.Hour, "ok")
+
+	tRunFail(t, func(t testing.TB) {
+		mClock := quartz.NewMock(t).WithMaxDuration(24 * time.Hour)
+		// seconds mistakenly multiplied by time.Second twice
+		mClock.NewTimer(5*time.Second*time.Second, "unit bug")
+	})
+}
+
+func TestWithTimeReport(t *testing.T) {
+	t.Parallel()
+	tl := &testLogger{}
+	t.Run("sub", func(t *testing.T) {
+		mClock := quartz.NewMock(t).WithLogger(tl).WithTimeReport()
+		mClock.Set(mClock.Now().Add(-time.Minute))
+		mClock.Advance(time.Hour)
+	})
+	expect := "] simulated 1h0m0s of virtual time in "
+	for _, c := range tl.calls {
+		if strings.Contains(c, expect) {
+			return
+		}
+	}
+	t.Fatalf("expected log line containing %q, got %v", expect, tl.calls)
+}
+
+type componentKey struct{}
+
+func TestWithContextTags(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mClock := quartz.NewMock(t).WithContextTags(componentKey{})
+	trap := mClock.Trap().TickerFunc("reaper")
+	defer trap.Close()
+
+	tickerCtx, tickerCancel := context.WithCancel(context.WithValue(ctx, componentKey{}, "reaper"))
+	defer tickerCancel()
+	go mClock.TickerFunc(tickerCtx, time.Second, func() error { return nil }, "explicit")
+	c := trap.MustWait(ctx)
+	c.MustRelease(ctx)
+	if len(c.Tags) != 2 || c.Tags[0] != "explicit" || c.Tags[1] != "reaper" {
+		t.Fatalf("unexpected tags %v", c.Tags)
+	}
+}
+
+func TestTestContextLifetime(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var tkr quartz.Waiter
+	var trap *quartz.Trap
+	t.Run("sub", func(t *testing.T) {
+		mClock := quartz.NewMock(t)
+		// neither canceled nor closed by the test
+		tkr = mClock.TickerFunc(context.Background(), time.Second, func() error { return nil })
+		trap = mClock.Trap().Now()
+	})
+	if err := tkr.Wait(); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if _, err := trap.Wait(ctx); !errors.Is(err, quartz.ErrTrapClosed) {
+		t.Fatalf("expected ErrTrapClosed, got %v", err)
+	}
+}
+
+// TestSimultaneousEventsConcurrent tests that events scheduled for the same instant are dispatched
+// concurrently, by scheduling two callbacks that each wait for the other to start.
+func TestSimultaneousEventsConcurrent(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mClock := quartz.NewMock(t)
+	var wg sync.WaitGroup
+	wg.Add(2)
+	for i := 0; i < 2; i++ {
+		mClock.AfterFunc(time.Second, func() {
+			wg.Done()
+			wg.Wait()
+		})
+	}
+	mClock.Advance(time.Second).MustWait(ctx)
+}
+
+func TestWithWorkerPool(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mClock := quartz.NewMock(t).WithWorkerPool(1)
+	started := make(chan struct{}, 2)
+	release := make(chan struct{})
+	for i := 0; i < 2; i++ {
+		mClock.AfterFunc(time.Second, func() {
+			started <- struct{}{}
+			<-release
+		})
+	}
+	w := mClock.Advance(time.Second)
+	<-started
+	select {
+	case <-started:
+		t.Fatal("second callback started while the only worker was busy")
+	case <-time.After(50 * time.Millisecond):
+		// OK
+	}
+	close(release)
+	<-started
+	w.MustWait(ctx)
+}
+
+func TestStringers(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mClock := quartz.NewMock(t)
+	tmr := mClock.NewTimer(time.Second, "tmr")
+	expect := "Timer(NewTimer(1s, [tmr]) at 2024-01-01 00:00:01 +0000 UTC, active)"
+	if got := fmt.Sprint(tmr); got != expect {
+		t.Fatalf("expected %q, got %q", expect, got)
+	}
+	tkr := mClock.NewTicker(time.Minute, "tkr")
+	defer tkr.Stop()
+	expect = "Mock(2024-01-01T00:00:00Z, 2 events, next in 1s, 0 traps)"
+	if got := fmt.Sprint(mClock); got != expect {
+		t.Fatalf("expected %q, got %q", expect, got)
+	}
+
+	w := mClock.Advance(time.Second)
+	w.MustWait(ctx)
+	expect = "AdvanceWaiter(done, 1 events, pending [])"
+	if got := fmt.Sprint(w); got != expect {
+		t.Fatalf("expected %q, got %q", expect, got)
 	}
 
-	select {
-	case got := <-timeCh:
-		end := mClock.Now("end")
-		if !got.Equal(end) {
-			t.Fatalf("expected %s got %s", end, got)
-		}
-	case <-testCtx.Done():
-		t.Fatal("timed out waiting for Now()")
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	logger.Info("debug", "timer", tmr, "ticker", tkr)
+	expect = "level=INFO msg=debug " +
+		"timer.kind=NewTimer timer.tags=[tmr] timer.duration=1s timer.deadline=2024-01-01T00:00:01.000Z timer.state=stopped " +
+		"ticker.kind=NewTicker ticker.tags=[tkr] ticker.duration=1m0s ticker.deadline=2024-01-01T00:01:00.000Z ticker.state=active\n"
+	if got := buf.String(); got != expect {
+		t.Fatalf("expected %q, got %q", expect, got)
 	}
 }
 
-func Test_MultipleTrapsDeadlock(t *testing.T) {
+func TestWithSyncTicks(t *testing.T) {
 	t.Parallel()
-	tRunFail(t, func(t testing.TB) {
-		testCtx, testCancel := context.WithTimeout(context.Background(), 2*time.Second)
-		defer testCancel()
-		mClock := quartz.NewMock(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
 
-		trap0 := mClock.Trap().Now("0")
-		defer trap0.Close()
-		trap1 := mClock.Trap().Now("1")
-		defer trap1.Close()
+	mClock := quartz.NewMock(t).WithSyncTicks("sync")
+	syncTkr := mClock.NewTicker(time.Second, "sync")
+	defer syncTkr.Stop()
+	asyncTkr := mClock.NewTicker(2*time.Second, "async")
+	defer asyncTkr.Stop()
 
-		go func() {
-			mClock.Now("0", "1")
-		}()
+	w := mClock.Advance(time.Second)
+	select {
+	case <-w.Done():
+		t.Fatal("advance completed before the tick was received")
+	case <-time.After(50 * time.Millisecond):
+		// OK
+	}
+	if pending := w.Pending(); len(pending) != 1 || pending[0].Tags[0] != "sync" {
+		t.Fatalf("expected sync ticker to be pending, got %v", pending)
+	}
+	<-syncTkr.C
+	w.MustWait(ctx)
 
-		c0 := trap0.MustWait(testCtx)
-		c0.MustRelease(testCtx) // deadlocks, test failure
-	})
+	// both tickers tick, but only the synchronous one needs a reader for the advance to complete.
+	go func() {
+		<-syncTkr.C
+	}()
+	mClock.Advance(time.Second).MustWait(ctx)
 }
 
-func Test_UnreleasedCalls(t *testing.T) {
+func TestWithFailOnIdleAdvance(t *testing.T) {
 	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mClock := quartz.NewMock(t).WithFailOnIdleAdvance()
+	mClock.NewTimer(time.Second)
+	mClock.Advance(time.Second).MustWait(ctx)
+
 	tRunFail(t, func(t testing.TB) {
-		testCtx, testCancel := context.WithTimeout(context.Background(), 10*time.Second)
-		defer testCancel()
-		mClock := quartz.NewMock(t)
+		mClock := quartz.NewMock(t).WithFailOnIdleAdvance()
+		mClock.Advance(time.Second).MustWait(ctx)
+	})
+}
 
-		trap := mClock.Trap().Now()
-		defer trap.Close()
+func TestWithMonotonic(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
 
-		go func() {
-			_ = mClock.Now()
-		}()
+	mClock := quartz.NewMock(t)
+	wall := mClock.Now()
+	if strings.Contains(wall.String(), "m=") {
+		t.Fatalf("expected no monotonic reading by default: %s", wall)
+	}
+	mClock.WithMonotonic(true)
+	start := mClock.Now()
+	if !strings.Contains(start.String(), "m=") {
+		t.Fatalf("expected monotonic reading: %s", start)
+	}
+	if !start.Equal(wall) {
+		t.Fatalf("expected %s got %s", wall, start)
+	}
+	mClock.Advance(time.Second).MustWait(ctx)
+	end := mClock.Now()
+	if d := end.Sub(start); d != time.Second {
+		t.Fatalf("expected 1s got %s", d)
+	}
+	if !end.Round(0).Equal(wall.Add(time.Second)) {
+		t.Fatalf("expected %s got %s", wall.Add(time.Second), end)
+	}
+	if strings.Contains(mClock.WithMonotonic(false).Now().String(), "m=") {
+		t.Fatal("expected monotonic reading to be stripped")
+	}
+}
 
-		c := trap.MustWait(testCtx) // missing release
-		trap.Close()                // detect unreleased call and fail
+func TestWithAutoIncrement(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
 
-		c.Release(testCtx) // clean up goroutine
-	})
+	mClock := quartz.NewMock(t).WithAutoIncrement(time.Microsecond)
+	start := mClock.Now()
+	mClock.NewTimer(3 * time.Microsecond)
+	for i := 1; i <= 4; i++ {
+		now := mClock.Now()
+		if want := start.Add(time.Duration(i) * time.Microsecond); !now.Equal(want) {
+			t.Fatalf("expected %s got %s", want, now)
+		}
+	}
+	// blocked by the timer until it fires
+	prev := start.Add(4 * time.Microsecond)
+	if now := mClock.Now(); !now.Equal(prev) {
+		t.Fatalf("expected %s got %s", prev, now)
+	}
+	mClock.Advance(0).MustWait(ctx)
+	if now := mClock.Now(); !now.Equal(prev) {
+		t.Fatalf("expected %s got %s", prev, now)
+	}
+	if now := mClock.Now(); !now.After(prev) {
+		t.Fatalf("expected time after %s got %s", prev, now)
+	}
 }
 
-func Test_WithLogger(t *testing.T) {
+func TestAdvanceBatch(t *testing.T) {
 	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
 
-	tl := &testLogger{}
-	mClock := quartz.NewMock(t).WithLogger(tl)
-	mClock.Now("test", "Test_WithLogger")
-	if len(tl.calls) != 1 {
-		t.Fatalf("expected 1 call, got %d", len(tl.calls))
+	mClock := quartz.NewMock(t)
+	start := mClock.Now()
+	var mu sync.Mutex
+	var ticks []time.Time
+	w := mClock.TickerFunc(ctx, 10*time.Millisecond, func() error {
+		mu.Lock()
+		defer mu.Unlock()
+		ticks = append(ticks, mClock.Now())
+		return nil
+	})
+
+	ds := make([]time.Duration, 1000)
+	for i := range ds {
+		ds[i] = time.Millisecond
 	}
-	expectLogLine := "Mock Clock - Now([test Test_WithLogger]) call, matched 0 traps"
-	if tl.calls[0] != expectLogLine {
-		t.Fatalf("expected log line %q, got %q", expectLogLine, tl.calls[0])
+	mClock.AdvanceBatch(ds...).MustWait(ctx)
+	if got := mClock.Since(start); got != time.Second {
+		t.Fatalf("expected to advance 1s, got %s", got)
 	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(ticks) != 100 {
+		t.Fatalf("expected 100 ticks, got %d", len(ticks))
+	}
+	for i, tick := range ticks {
+		if want := start.Add(time.Duration(i+1) * 10 * time.Millisecond); !tick.Equal(want) {
+			t.Fatalf("tick %d: expected %s got %s", i, want, tick)
+		}
+	}
+	cancel()
+	_ = w.Wait()
+}
+
+func TestAdvanceWaiter_Events(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
 
+	mClock := quartz.NewMock(t)
 	mClock.NewTimer(time.Second, "timer")
-	if len(tl.calls) != 2 {
-		t.Fatalf("expected 2 calls, got %d", len(tl.calls))
+	started := make(chan struct{})
+	block := make(chan struct{})
+	mClock.AfterFunc(time.Second, func() {
+		close(started)
+		<-block
+	}, "slow")
+	mClock.AfterFunc(2*time.Second, func() {}, "later")
+
+	w := mClock.Advance(time.Second)
+	<-started
+	var slow *quartz.EventInfo
+	for _, e := range w.Pending() {
+		if e.Kind == "AfterFunc" && e.Tags[0] == "slow" {
+			slow = &e
+		}
 	}
-	expectLogLine = "Mock Clock - NewTimer(1s, [timer]) call, matched 0 traps"
-	if tl.calls[1] != expectLogLine {
-		t.Fatalf("expected log line %q, got %q", expectLogLine, tl.calls[1])
+	if slow == nil {
+		t.Fatalf("expected slow AfterFunc to be pending, got %v", w.Pending())
 	}
-
-	mClock.Advance(500 * time.Millisecond)
-	if len(tl.calls) != 3 {
-		t.Fatalf("expected 3 calls, got %d", len(tl.calls))
+	if slow.Duration != time.Second {
+		t.Fatalf("expected duration 1s, got %s", slow.Duration)
 	}
-	expectLogLine = "Mock Clock - Advance(500ms)"
-	if tl.calls[2] != expectLogLine {
-		t.Fatalf("expected log line %q, got %q", expectLogLine, tl.calls[2])
+	close(block)
+	w.MustWait(ctx)
+	if events := w.Events(); len(events) != 2 {
+		t.Fatalf("expected 2 events, got %v", events)
+	}
+	if pending := w.Pending(); len(pending) != 0 {
+		t.Fatalf("expected no pending events, got %v", pending)
 	}
 }
 
-type captureFailTB struct {
-	failed bool
-	testing.TB
-}
+func TestTimerStats(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
 
-func (t *captureFailTB) Errorf(format string, args ...any) {
-	t.Helper()
-	t.Logf(format, args...)
-	t.failed = true
-}
+	mClock := quartz.NewMock(t)
+	for i := 0; i < 3; i++ {
+		tmr := mClock.NewTimer(time.Second, "loop")
+		tmr.Stop()
+	}
+	tmr := mClock.NewTimer(time.Second, "loop")
+	tmr.Reset(2 * time.Second)
+	mClock.AfterFunc(time.Second, func() {}, "other")
+	mClock.Advance(time.Second).MustWait(ctx)
+	mClock.Advance(time.Second).MustWait(ctx)
+	tmr.Stop() // already fired
 
-func (t *captureFailTB) Error(args ...any) {
-	t.Helper()
-	t.Log(args...)
-	t.failed = true
+	got := mClock.TimerStats("loop")
+	want := quartz.TimerStats{Created: 4, Stopped: 3, Fired: 1, Reset: 1}
+	if got != want {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+	if all := mClock.TimerStats(); all.Created != 5 || all.Fired != 2 {
+		t.Fatalf("unexpected stats for all timers %+v", all)
+	}
 }
 
-func (t *captureFailTB) Fatal(args ...any) {
-	t.Helper()
-	t.Log(args...)
-	t.failed = true
-}
+func TestBuffered(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
 
-func (t *captureFailTB) Fatalf(format string, args ...any) {
-	t.Helper()
-	t.Logf(format, args...)
-	t.failed = true
+	mClock := quartz.NewMock(t)
+	tkr := mClock.NewTicker(time.Second)
+	defer tkr.Stop()
+	tmr := mClock.NewTimer(time.Second)
+	if n := tkr.Buffered(); n != 0 {
+		t.Fatalf("expected no buffered ticks, got %d", n)
+	}
+	mClock.Advance(time.Second).MustWait(ctx)
+	mClock.Advance(time.Second).MustWait(ctx)
+	if n := tkr.Buffered(); n != 1 {
+		t.Fatalf("expected exactly one buffered tick, got %d", n)
+	}
+	if n := tmr.Buffered(); n != 1 {
+		t.Fatalf("expected the timer to be buffered, got %d", n)
+	}
+	<-tkr.C
+	<-tmr.C
+	if n := tkr.Buffered(); n != 0 {
+		t.Fatalf("expected no buffered ticks after receiving, got %d", n)
+	}
+	if n := tmr.Buffered(); n != 0 {
+		t.Fatalf("expected the timer not to be buffered after receiving, got %d", n)
+	}
 }
 
-func (t *captureFailTB) Fail() {
-	t.failed = true
-}
+func TestTickerEvents(t *testing.T) {
+	t.Parallel()
 
-func (t *captureFailTB) FailNow() {
-	t.failed = true
-}
+	mClock := quartz.NewMock(t)
+	start := mClock.Now()
+	tkr := mClock.NewTicker(time.Second, "poll")
+	defer tkr.Stop()
+	other := mClock.NewTicker(time.Minute, "other")
+	defer other.Stop()
+	mClock.Advance(time.Second)
+	tkr.Reset(500 * time.Millisecond)
+	tkr.Stop()
 
-func (t *captureFailTB) Failed() bool {
-	return t.failed
+	events := mClock.TickerEvents("poll")
+	want := []quartz.TickerEvent{
+		{Kind: "NewTicker", Tags: []string{"poll"}, Time: start, NewPeriod: time.Second},
+		{Kind: "Ticker.Reset", Tags: []string{"poll"}, Time: start.Add(time.Second),
+			OldPeriod: time.Second, NewPeriod: 500 * time.Millisecond},
+		{Kind: "Ticker.Stop", Tags: []string{"poll"}, Time: start.Add(time.Second),
+			OldPeriod: 500 * time.Millisecond},
+	}
+	if !reflect.DeepEqual(events, want) {
+		t.Fatalf("expected %v, got %v", want, events)
+	}
 }
 
-func tRunFail(t testing.TB, f func(t testing.TB)) {
-	tb := &captureFailTB{TB: t}
-	f(tb)
-	if !tb.Failed() {
-		t.Fatal("want test to fail")
+func TestWithWaitProgress(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	tl := &testLogger{}
+	mClock := quartz.NewMock(t).WithLogger(tl).WithWaitProgress(10 * time.Millisecond)
+	block := make(chan struct{})
+	mClock.AfterFunc(time.Second, func() {
+		<-block
+	}, "slow")
+	w := mClock.Advance(time.Second)
+	time.AfterFunc(100*time.Millisecond, func() { close(block) })
+	w.MustWait(ctx)
+
+	var progress []string
+	for _, l := range tl.calls {
+		if strings.Contains(l, "AdvanceWaiter still waiting") {
+			progress = append(progress, l)
+		}
+	}
+	if len(progress) == 0 {
+		t.Fatalf("expected progress to be logged, got %v", tl.calls)
+	}
+	if !strings.Contains(progress[0], "AfterFunc") || !strings.Contains(progress[0], "slow") {
+		t.Fatalf("expected progress to report the slow AfterFunc, got %q", progress[0])
 	}
 }
 
-type testLogger struct {
-	calls []string
+func TestAdvanceWaiter_Fired(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mClock := quartz.NewMock(t)
+	start := mClock.Now()
+	mClock.AfterFunc(time.Second, func() {}, "a")
+	mClock.AfterFunc(2*time.Second, func() {}, "b")
+	mClock.AfterFunc(time.Hour, func() {}, "late")
+
+	w := mClock.AdvanceTo(start.Add(2 * time.Second))
+	w.MustWait(ctx)
+	fired := w.Fired()
+	if len(fired) != 2 {
+		t.Fatalf("expected 2 events, got %v", fired)
+	}
+	for i, tag := range []string{"a", "b"} {
+		at := start.Add(time.Duration(i+1) * time.Second)
+		if fired[i].Kind != "AfterFunc" || fired[i].Tags[0] != tag || !fired[i].FiredAt.Equal(at) {
+			t.Errorf("expected AfterFunc [%s] at %s, got %s", tag, at, fired[i])
+		}
+	}
+
+	w = mClock.Jump(start.Add(2 * time.Hour))
+	w.MustWait(ctx)
+	fired = w.Fired()
+	if len(fired) != 1 || !fired[0].Deadline.Equal(start.Add(time.Hour)) ||
+		!fired[0].FiredAt.Equal(start.Add(2*time.Hour)) {
+		t.Fatalf("expected the late event to fire at the jump target, got %v", fired)
+	}
 }
 
-func (l *testLogger) Log(args ...any) {
-	l.calls = append(l.calls, fmt.Sprint(args...))
+func TestAdvanceStatus(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mClock := quartz.NewMock(t)
+	if mClock.Advancing() {
+		t.Fatal("expected not to be advancing")
+	}
+	started := make(chan struct{})
+	block := make(chan struct{})
+	mClock.AfterFunc(time.Second, func() {
+		close(started)
+		<-block
+	}, "stuck")
+	mClock.AfterFunc(2*time.Second, func() {}, "later")
+
+	w := mClock.Advance(time.Second)
+	<-started
+	if !mClock.Advancing() {
+		t.Fatal("expected to be advancing")
+	}
+	st := mClock.AdvanceStatus()
+	if !st.Target.Equal(st.Now) || st.Remaining != 0 {
+		t.Fatalf("unexpected status %+v", st)
+	}
+	if len(st.InFlight) != 1 || st.InFlight[0].Tags[0] != "stuck" {
+		t.Fatalf("expected stuck callback in flight, got %v", st.InFlight)
+	}
+	close(block)
+	w.MustWait(ctx)
+	if mClock.Advancing() {
+		t.Fatal("expected not to be advancing")
+	}
 }
 
-func (l *testLogger) Logf(format string, args ...any) {
-	l.calls = append(l.calls, fmt.Sprintf(format, args...))
+func TestTrap_TimerStopReset(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mClock := quartz.NewMock(t)
+	resetTrap := mClock.Trap().TimerReset("keepalive")
+	defer resetTrap.Close()
+	stopTrap := mClock.Trap().TimerStop("keepalive")
+	defer stopTrap.Close()
+
+	tmr := mClock.NewTimer(30*time.Second, "keepalive")
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		tmr.Reset(45*time.Second, "keepalive")
+		tmr.Stop("keepalive")
+	}()
+	c := resetTrap.MustWait(ctx)
+	if c.Duration != 45*time.Second {
+		t.Fatalf("expected reset duration 45s, got %s", c.Duration)
+	}
+	c.MustRelease(ctx)
+	if d, ok := mClock.Peek(); !ok || d != 45*time.Second {
+		t.Fatalf("expected timer in 45s, got %s", d)
+	}
+	stopTrap.MustWait(ctx).MustRelease(ctx)
+	<-done
+	if _, ok := mClock.Peek(); ok {
+		t.Fatal("expected timer to be stopped")
+	}
 }
 
 func TestTimerStop_Go123(t *testing.T) {
@@ -632,3 +3121,57 @@ func diff(a, b []string) string {
 	}
 	return strings.Join(leaks, "\n\n")
 }
+
+func TestEpochEnv(t *testing.T) {
+	t.Setenv(quartz.EpochEnv, "2024-02-29T23:59:59Z")
+	mClock := quartz.NewMock(t)
+	if now := mClock.Now(); !now.Equal(time.Date(2024, 2, 29, 23, 59, 59, 0, time.UTC)) {
+		t.Fatalf("unexpected start time %s", now)
+	}
+}
+
+func TestPerturbation(t *testing.T) {
+	t.Parallel()
+
+	schedule := func(seed int64) []time.Duration {
+		mClock := quartz.NewMock(t).WithSeed(seed).WithPerturbation(0.2)
+		mClock.NewTimer(5*time.Second, "a")
+		mClock.NewTimer(5*time.Second, "b")
+		var ds []time.Duration
+		var elapsed time.Duration
+		for {
+			d, ok := mClock.Peek()
+			if !ok {
+				return ds
+			}
+			elapsed += d
+			if elapsed < 4*time.Second || elapsed > 6*time.Second {
+				t.Fatalf("perturbed duration %s out of range", elapsed)
+			}
+			ds = append(ds, elapsed)
+			_, w := mClock.AdvanceNext()
+			w.MustWait(context.Background())
+		}
+	}
+	first := schedule(42)
+	if len(first) != 2 {
+		t.Fatalf("expected timers to fire separately, got %v", first)
+	}
+	second := schedule(42)
+	if first[0] != second[0] || first[1] != second[1] {
+		t.Fatalf("expected same schedule for same seed, got %v and %v", first, second)
+	}
+}
+
+func TestSeedEnv(t *testing.T) {
+	t.Setenv(quartz.SeedEnv, "1234")
+	tl := &testLogger{}
+	quartz.NewMock(t).WithLogger(tl).WithPerturbation(0.1)
+	if len(tl.calls) != 1 {
+		t.Fatalf("expected 1 call, got %d", len(tl.calls))
+	}
+	expectLogLine := "Mock Clock - [2024-01-01T00:00:00Z +0s] using seed 1234 (from QUARTZ_SEED)"
+	if tl.calls[0] != expectLogLine {
+		t.Fatalf("expected log line %q, got %q", expectLogLine, tl.calls[0])
+	}
+}
@@ -0,0 +1,55 @@
+package quartz
+
+import "time"
+
+// WithMonotonicityCheck causes the Mock to fail the test if the time observed by the code under
+// test goes backwards: if Now returns a time before one it returned earlier, or a timer or ticker
+// delivers a time before one delivered earlier. This guards against bugs in the Mock, and against
+// fixtures that misuse Set. Moving the clock backwards explicitly, with Set, is expected and resets
+// the check.
+func (m *Mock) WithMonotonicityCheck() *Mock {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.monotonicity = &monotonicityCheck{}
+	return m
+}
+
+// monotonicityCheck records the latest times observed by the code under test.
+type monotonicityCheck struct {
+	now       time.Time
+	delivered time.Time
+}
+
+// observeNowLocked checks a time returned by Now.
+func (m *Mock) observeNowLocked(t time.Time) {
+	if m.monotonicity == nil {
+		return
+	}
+	m.checkMonotonicLocked("Now", &m.monotonicity.now, t)
+}
+
+// observeDeliveredLocked checks a time delivered by a timer or ticker.
+func (m *Mock) observeDeliveredLocked(kind string, t time.Time) {
+	if m.monotonicity == nil {
+		return
+	}
+	m.checkMonotonicLocked(kind, &m.monotonicity.delivered, t)
+}
+
+func (m *Mock) checkMonotonicLocked(kind string, last *time.Time, t time.Time) {
+	if t.Before(*last) {
+		m.tb.Helper()
+		m.tb.Errorf("time went backwards: %s observed %s, after %s was observed",
+			kind, t.Format(time.RFC3339Nano), last.Format(time.RFC3339Nano))
+		return
+	}
+	*last = t
+}
+
+// resetMonotonicityLocked resets the check after the clock was explicitly moved backwards.
+func (m *Mock) resetMonotonicityLocked() {
+	if m.monotonicity == nil {
+		return
+	}
+	m.monotonicity = &monotonicityCheck{}
+}
@@ -0,0 +1,111 @@
+package quartz_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/coder/quartz"
+)
+
+// TestTrapParity asserts that every method of the Clock interface, and every method that accepts
+// tags on the values it returns, has a corresponding Trapper method, and vice versa, apart from
+// the traps for Mock methods used by test harnesses.
+func TestTrapParity(t *testing.T) {
+	t.Parallel()
+
+	// prefixes for the trap names of methods on the types returned by Clock methods. Types not
+	// listed use the name of the Clock method that returns them, e.g. TickerFuncWait.
+	typePrefixes := map[reflect.Type]string{
+		reflect.TypeOf(&quartz.Timer{}):  "Timer",
+		reflect.TypeOf(&quartz.Ticker{}): "Ticker",
+	}
+	isTrappable := func(m reflect.Type) bool {
+		if !m.IsVariadic() {
+			return false
+		}
+		return m.In(m.NumIn()-1) == reflect.TypeOf([]string{})
+	}
+
+	want := map[string]bool{"Advance": true, "AdvanceNext": true, "Set": true}
+	clockType := reflect.TypeOf((*quartz.Clock)(nil)).Elem()
+	for i := 0; i < clockType.NumMethod(); i++ {
+		cm := clockType.Method(i)
+		if !isTrappable(cm.Type) {
+			t.Errorf("Clock.%s does not accept tags", cm.Name)
+		}
+		want[cm.Name] = true
+		for j := 0; j < cm.Type.NumOut(); j++ {
+			out := cm.Type.Out(j)
+			prefix, ok := typePrefixes[out]
+			if !ok {
+				prefix = cm.Name
+			}
+			for k := 0; k < out.NumMethod(); k++ {
+				om := out.Method(k)
+				if isTrappable(om.Type) {
+					want[prefix+om.Name] = true
+				}
+			}
+		}
+	}
+
+	trapperType := reflect.TypeOf(quartz.Trapper{})
+	trapType := reflect.TypeOf(&quartz.Trap{})
+	got := make(map[string]bool)
+	for i := 0; i < trapperType.NumMethod(); i++ {
+		tm := trapperType.Method(i)
+		if tm.Type.NumOut() != 1 || tm.Type.Out(0) != trapType {
+			continue
+		}
+		got[tm.Name] = true
+	}
+
+	for name := range want {
+		if !got[name] {
+			t.Errorf("missing Trapper.%s", name)
+		}
+	}
+	for name := range got {
+		if !want[name] {
+			t.Errorf("Trapper.%s does not correspond to a Clock method", name)
+		}
+	}
+}
+
+// TestExpectParity asserts that every call that can be trapped can also be expected, apart from
+// the Mock methods used by test harnesses.
+func TestExpectParity(t *testing.T) {
+	t.Parallel()
+
+	harness := map[string]bool{"Advance": true, "AdvanceNext": true, "Set": true}
+	trapType := reflect.TypeOf(&quartz.Trap{})
+	want := make(map[string]bool)
+	trapperType := reflect.TypeOf(quartz.Trapper{})
+	for i := 0; i < trapperType.NumMethod(); i++ {
+		tm := trapperType.Method(i)
+		if tm.Type.NumOut() == 1 && tm.Type.Out(0) == trapType && !harness[tm.Name] {
+			want[tm.Name] = true
+		}
+	}
+
+	expectationType := reflect.TypeOf(&quartz.Expectation{})
+	got := make(map[string]bool)
+	expecterType := reflect.TypeOf(quartz.Expecter{})
+	for i := 0; i < expecterType.NumMethod(); i++ {
+		em := expecterType.Method(i)
+		if em.Type.NumOut() == 1 && em.Type.Out(0) == expectationType {
+			got[em.Name] = true
+		}
+	}
+
+	for name := range want {
+		if !got[name] {
+			t.Errorf("missing Expecter.%s", name)
+		}
+	}
+	for name := range got {
+		if !want[name] {
+			t.Errorf("Expecter.%s does not correspond to a Trapper method", name)
+		}
+	}
+}
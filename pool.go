@@ -0,0 +1,95 @@
+package quartz
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// WithWorkerPool causes the Mock to run timer and tick events, including the functions passed to
+// AfterFunc and TickerFunc, on a pool of n worker goroutines, rather than on a new goroutine for
+// each event. This simulates production environments where callback concurrency is limited, e.g.
+// to check that the code under test makes progress with a single worker.
+//
+// Events that fire while all workers are busy wait for a worker to become free, and the
+// AdvanceWaiter waits for them too. Callbacks that block until another callback runs can therefore
+// deadlock if n is too small; that is usually exactly the bug this option is meant to find.
+//
+// The workers exit at the end of the test. If a callback is still running 10s later, e.g. because
+// it is blocked, the test fails rather than hanging.
+func (m *Mock) WithWorkerPool(n int) *Mock {
+	if n <= 0 {
+		panic("WithWorkerPool called with non-positive size")
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.pool != nil {
+		panic("WithWorkerPool called more than once")
+	}
+	p := &workerPool{work: make(chan func()), closing: make(chan struct{})}
+	p.wg.Add(n)
+	for i := 0; i < n; i++ {
+		go p.worker()
+	}
+	m.pool = p
+	m.tb.Cleanup(func() { p.close(m.tb) })
+	return m
+}
+
+// workerPool runs functions on a fixed number of goroutines.
+type workerPool struct {
+	work      chan func()
+	closing   chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+}
+
+func (p *workerPool) worker() {
+	defer p.wg.Done()
+	for {
+		select {
+		case f := <-p.work:
+			f()
+		case <-p.closing:
+			return
+		}
+	}
+}
+
+// run runs f on the pool, waiting for a free worker. Once the pool is closed, f is run on a new
+// goroutine instead.
+func (p *workerPool) run(f func()) {
+	select {
+	case p.work <- f:
+	case <-p.closing:
+		go f()
+	}
+}
+
+// poolCloseTimeout is how long closing the pool waits for busy workers to finish.
+const poolCloseTimeout = 10 * time.Second
+
+// close stops the workers, and fails the test if they are still busy after poolCloseTimeout, e.g.
+// because a callback is blocked, rather than hanging the test.
+func (p *workerPool) close(tb testing.TB) {
+	p.closeOnce.Do(func() { close(p.closing) })
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(poolCloseTimeout):
+		tb.Errorf("worker pool still busy %s after the end of the test; is a callback blocked?", poolCloseTimeout)
+	}
+}
+
+// goFireLocked fires the event on a new goroutine, or on the worker pool, if enabled.
+func (m *Mock) goFireLocked(e event, t time.Time) {
+	if m.pool == nil {
+		go e.fire(t)
+		return
+	}
+	go m.pool.run(func() { e.fire(t) })
+}
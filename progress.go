@@ -0,0 +1,61 @@
+package quartz
+
+import (
+	"context"
+	"time"
+)
+
+// WithWaitProgress causes AdvanceWaiter.Wait and MustWait to log, every interval of real time they
+// spend waiting, the events triggered by the advance that are still running, e.g. an AfterFunc or
+// TickerFunc callback that is blocked. This turns a test that hangs silently until its context
+// expires into one that reports which callbacks it is stuck on:
+//
+//	mClock := quartz.NewMock(t).WithWaitProgress(time.Second)
+//
+// An interval of zero or less disables the logging, which is the default.
+func (m *Mock) WithWaitProgress(interval time.Duration) *Mock {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.waitProgress = interval
+	return m
+}
+
+// wait waits for the advance to complete, or the context to expire, logging progress as configured
+// by WithWaitProgress.
+func (w AdvanceWaiter) wait(ctx context.Context) error {
+	var interval time.Duration
+	if w.mock != nil {
+		w.mock.mu.Lock()
+		interval = w.mock.waitProgress
+		w.mock.mu.Unlock()
+	}
+	var tick <-chan time.Time
+	if interval > 0 {
+		tkr := time.NewTicker(interval)
+		defer tkr.Stop()
+		tick = tkr.C
+	}
+	start := time.Now()
+	for {
+		select {
+		case <-w.ch:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-tick:
+			w.logProgress(time.Since(start))
+		}
+	}
+}
+
+func (w AdvanceWaiter) logProgress(elapsed time.Duration) {
+	pending := w.Pending()
+	m := w.mock
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.testOver {
+		return
+	}
+	m.logfLocked("AdvanceWaiter still waiting after %s; %d events running: %v",
+		elapsed.Round(time.Millisecond), len(pending), pending)
+}
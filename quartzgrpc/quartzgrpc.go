@@ -0,0 +1,85 @@
+// Package quartzgrpc provides gRPC interceptors that install a quartz.Clock into the context of
+// each RPC, so that services written against quartz.FromContext can be tested end to end with a
+// mock clock.
+package quartzgrpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/coder/quartz"
+)
+
+// UnaryServerInterceptor returns a server interceptor that installs clk into the context of each
+// unary RPC. If the RPC has a deadline, the handler's context instead has a virtual deadline: the
+// same amount of time after clk.Now() that remained before the real deadline, and is canceled
+// with context.DeadlineExceeded once that elapses on clk, rather than in real time. It is still
+// canceled if the RPC is canceled for any other reason.
+func UnaryServerInterceptor(clk quartz.Clock) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, cancel := serverContext(ctx, clk)
+		defer cancel()
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor returns a server interceptor that installs clk into the context of each
+// streaming RPC, deriving a virtual deadline like UnaryServerInterceptor.
+func StreamServerInterceptor(clk quartz.Clock) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, cancel := serverContext(ss.Context(), clk)
+		defer cancel()
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// UnaryClientInterceptor returns a client interceptor that installs clk into the context of each
+// outgoing unary RPC.
+func UnaryClientInterceptor(clk quartz.Clock) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(quartz.NewContext(ctx, clk), method, req, reply, cc, opts...)
+	}
+}
+
+// StreamClientInterceptor returns a client interceptor that installs clk into the context of each
+// outgoing streaming RPC.
+func StreamClientInterceptor(clk quartz.Clock) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(quartz.NewContext(ctx, clk), desc, cc, method, opts...)
+	}
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// serverContext installs clk into ctx, and replaces its real deadline, if any, with a virtual one
+// on clk. The returned context keeps the values of ctx, and is canceled when ctx is, except by its
+// real deadline passing.
+func serverContext(ctx context.Context, clk quartz.Clock) (context.Context, context.CancelFunc) {
+	ctx = quartz.NewContext(ctx, clk)
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return ctx, func() {}
+	}
+	detached, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	stop := context.AfterFunc(ctx, func() {
+		if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			cancel()
+		}
+	})
+	vctx, vcancel := clk.WithTimeout(detached, time.Until(deadline), "quartzgrpc", "deadline")
+	return vctx, func() {
+		vcancel()
+		stop()
+		cancel()
+	}
+}
@@ -0,0 +1,86 @@
+package quartzgrpc_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/coder/quartz"
+	"github.com/coder/quartz/quartzgrpc"
+)
+
+func TestUnaryServerInterceptor_VirtualDeadline(t *testing.T) {
+	t.Parallel()
+	testCtx, testCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer testCancel()
+
+	mClock := quartz.NewMock(t)
+	trap := mClock.Trap().WithTimeout("quartzgrpc", "deadline")
+	defer trap.Close()
+
+	rpcCtx, rpcCancel := context.WithTimeout(testCtx, 5*time.Second)
+	defer rpcCancel()
+	interceptor := quartzgrpc.UnaryServerInterceptor(mClock)
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := interceptor(rpcCtx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, _ any) (any, error) {
+			if quartz.FromContext(ctx) != quartz.Clock(mClock) {
+				return nil, errors.New("wrong clock in context")
+			}
+			deadline, ok := ctx.Deadline()
+			if !ok || deadline.After(mClock.Now().Add(5*time.Second)) {
+				return nil, errors.New("expected virtual deadline")
+			}
+			<-ctx.Done()
+			return nil, ctx.Err()
+		})
+		errCh <- err
+	}()
+
+	c := trap.MustWait(testCtx)
+	c.MustRelease(testCtx)
+	mClock.Advance(c.Duration).MustWait(testCtx)
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected DeadlineExceeded, got %v", err)
+		}
+	case <-testCtx.Done():
+		t.Fatal("timeout waiting for handler")
+	}
+}
+
+func TestUnaryServerInterceptor_RealDeadlineIgnored(t *testing.T) {
+	t.Parallel()
+	testCtx, testCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer testCancel()
+
+	mClock := quartz.NewMock(t)
+	rpcCtx, rpcCancel := context.WithTimeout(testCtx, 10*time.Millisecond)
+	defer rpcCancel()
+	interceptor := quartzgrpc.UnaryServerInterceptor(mClock)
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := interceptor(rpcCtx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, _ any) (any, error) {
+			child, cancel := context.WithCancel(ctx)
+			defer cancel()
+			// the real deadline passes, but the Mock doesn't advance.
+			<-rpcCtx.Done()
+			time.Sleep(10 * time.Millisecond)
+			if err := child.Err(); err != nil {
+				return nil, err
+			}
+			_, w := mClock.AdvanceNext()
+			w.MustWait(testCtx)
+			<-child.Done()
+			return nil, child.Err()
+		})
+		errCh <- err
+	}()
+	if err := <-errCh; !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected DeadlineExceeded from the virtual deadline, got %v", err)
+	}
+}
@@ -0,0 +1,96 @@
+// Package quartzhttp installs a quartz.Clock into the context of HTTP requests, so that handlers
+// written against quartz.FromContext can be tested end to end with a mock clock.
+package quartzhttp
+
+import (
+	"net/http"
+
+	"github.com/coder/quartz"
+)
+
+// ClockHeader is the default request header used to select a clock per request.
+const ClockHeader = "X-Quartz-Clock"
+
+// Option configures the Middleware.
+type Option func(*middleware)
+
+// WithHeaderClocks selects the clock for each request by looking up the value of the given header
+// in clocks. Requests without the header, or with an unknown value, use the default clock. If
+// header is empty, ClockHeader is used.
+//
+// This allows end-to-end tests to freeze or skew time for individual requests sent to a shared
+// server, e.g. by sending requests through a RoundTripper with an ID set.
+func WithHeaderClocks(header string, clocks map[string]quartz.Clock) Option {
+	if header == "" {
+		header = ClockHeader
+	}
+	return func(m *middleware) {
+		m.header = header
+		m.clocks = clocks
+	}
+}
+
+type middleware struct {
+	clock  quartz.Clock
+	header string
+	clocks map[string]quartz.Clock
+	next   http.Handler
+}
+
+// Middleware returns HTTP middleware that installs clk into the context of each request, where it
+// can be retrieved with quartz.FromContext.
+func Middleware(clk quartz.Clock, opts ...Option) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		m := &middleware{clock: clk, next: next}
+		for _, o := range opts {
+			o(m)
+		}
+		return m
+	}
+}
+
+func (m *middleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	clk := m.clock
+	if m.header != "" {
+		if c, ok := m.clocks[r.Header.Get(m.header)]; ok {
+			clk = c
+		}
+	}
+	m.next.ServeHTTP(w, r.WithContext(quartz.NewContext(r.Context(), clk)))
+}
+
+// RoundTripper is an http.RoundTripper that installs Clock into the context of each outgoing
+// request, and, if ID is set, sets the ClockHeader so that a server using Middleware with
+// WithHeaderClocks can select a matching clock.
+type RoundTripper struct {
+	// Base is the underlying RoundTripper. If nil, http.DefaultTransport is used.
+	Base http.RoundTripper
+	// Clock is installed into the context of each request, if set.
+	Clock quartz.Clock
+	// ID is sent as the value of Header, if set.
+	ID string
+	// Header is the header ID is sent in. If empty, ClockHeader is used.
+	Header string
+}
+
+func (rt *RoundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	base := rt.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	ctx := r.Context()
+	if rt.Clock != nil {
+		ctx = quartz.NewContext(ctx, rt.Clock)
+	}
+	r = r.Clone(ctx)
+	if rt.ID != "" {
+		header := rt.Header
+		if header == "" {
+			header = ClockHeader
+		}
+		r.Header.Set(header, rt.ID)
+	}
+	return base.RoundTrip(r)
+}
+
+var _ http.RoundTripper = &RoundTripper{}
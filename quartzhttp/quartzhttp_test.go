@@ -0,0 +1,103 @@
+package quartzhttp_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/coder/quartz"
+	"github.com/coder/quartz/quartzhttp"
+)
+
+func TestMiddleware_HeaderClocks(t *testing.T) {
+	t.Parallel()
+
+	frozen := quartz.NewMock(t)
+	skewed := quartz.NewMock(t)
+	skewed.Advance(time.Hour)
+	handler := quartzhttp.Middleware(frozen, quartzhttp.WithHeaderClocks("", map[string]quartz.Clock{
+		"skewed": skewed,
+	}))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, quartz.FromContext(r.Context()).Now().Format(time.RFC3339))
+	}))
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+
+	get := func(client *http.Client) string {
+		t.Helper()
+		resp, err := client.Get(srv.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(body)
+	}
+
+	if got, want := get(srv.Client()), "2024-01-01T00:00:00Z"; got != want {
+		t.Fatalf("expected %s got %s", want, got)
+	}
+	client := &http.Client{Transport: &quartzhttp.RoundTripper{ID: "skewed"}}
+	if got, want := get(client), "2024-01-01T01:00:00Z"; got != want {
+		t.Fatalf("expected %s got %s", want, got)
+	}
+}
+
+func TestTokenServer(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mClock := quartz.NewMock(t)
+	srv := quartzhttp.NewTokenServer(mClock, time.Hour)
+	defer srv.Close()
+
+	resp, err := srv.Client().Post(srv.URL+"/token", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tok struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&tok)
+	resp.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tok.ExpiresIn != 3600 {
+		t.Fatalf("expected expires_in 3600, got %d", tok.ExpiresIn)
+	}
+
+	status := func() int {
+		t.Helper()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/api", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Authorization", "Bearer "+tok.AccessToken)
+		resp, err := srv.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	if got := status(); got != http.StatusOK {
+		t.Fatalf("expected 200, got %d", got)
+	}
+	mClock.Advance(time.Hour).MustWait(ctx)
+	if got := status(); got != http.StatusUnauthorized {
+		t.Fatalf("expected 401 after expiry, got %d", got)
+	}
+	if n := srv.Issued(); n != 1 {
+		t.Fatalf("expected 1 token issued, got %d", n)
+	}
+}
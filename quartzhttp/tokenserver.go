@@ -0,0 +1,89 @@
+package quartzhttp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coder/quartz"
+)
+
+// TokenServer is a fake auth server for testing token refresh logic. It issues bearer tokens that
+// expire after a TTL measured on a quartz.Clock, so that with a Mock, expiry is driven purely by
+// advancing the clock.
+//
+// POST requests to /token issue a token, with an OAuth2-style JSON response of the form
+// {"access_token": "...", "token_type": "Bearer", "expires_in": 3600}. Requests to any other path
+// succeed with 200 OK if they carry a valid token in the Authorization header, and fail with 401
+// Unauthorized otherwise.
+type TokenServer struct {
+	*httptest.Server
+
+	clock quartz.Clock
+	ttl   time.Duration
+
+	mu     sync.Mutex
+	tokens map[string]time.Time // token to expiry
+	issued int
+}
+
+// NewTokenServer starts a TokenServer that issues tokens valid for ttl on clk. The caller should
+// call Close when finished, to shut it down.
+func NewTokenServer(clk quartz.Clock, ttl time.Duration) *TokenServer {
+	s := &TokenServer{clock: clk, ttl: ttl, tokens: make(map[string]time.Time)}
+	s.Server = httptest.NewServer(s)
+	return s
+}
+
+// ServeHTTP implements http.Handler.
+func (s *TokenServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/token" {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token": s.Issue(),
+			"token_type":   "Bearer",
+			"expires_in":   int64(s.ttl / time.Second),
+		})
+		return
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || !s.Valid(token) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// Issue issues a new token, as if requested from /token.
+func (s *TokenServer) Issue() string {
+	expiry := s.clock.Now("quartzhttp", "token").Add(s.ttl)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.issued++
+	token := fmt.Sprintf("token-%d", s.issued)
+	s.tokens[token] = expiry
+	return token
+}
+
+// Valid returns whether the token was issued by the server and has not expired.
+func (s *TokenServer) Valid(token string) bool {
+	s.mu.Lock()
+	expiry, ok := s.tokens[token]
+	s.mu.Unlock()
+	return ok && s.clock.Now("quartzhttp", "token").Before(expiry)
+}
+
+// Issued returns the number of tokens issued so far, e.g. to assert how often a client refreshed.
+func (s *TokenServer) Issued() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.issued
+}
@@ -2,10 +2,13 @@ package quartz
 
 import (
 	"context"
+	"sync"
 	"time"
 )
 
-type realClock struct{}
+type realClock struct {
+	afterFuncExec func(fn func()) // see NewRealWithAfterFuncExecutor
+}
 
 func NewReal() Clock {
 	return realClock{}
@@ -16,28 +19,37 @@ func (realClock) NewTicker(d time.Duration, _ ...string) *Ticker {
 	return &Ticker{ticker: tkr, C: tkr.C}
 }
 
-func (realClock) TickerFunc(ctx context.Context, d time.Duration, f func() error, _ ...string) Waiter {
+func (realClock) TickerFunc(ctx context.Context, d time.Duration, f func() error, _ ...string) StopWaiter {
 	ct := &realContextTicker{
-		ctx: ctx,
-		tkr: time.NewTicker(d),
-		f:   f,
-		err: make(chan error, 1),
+		ctx:  ctx,
+		tkr:  time.NewTicker(d),
+		f:    f,
+		err:  make(chan error, 1),
+		stop: make(chan struct{}),
 	}
 	go ct.run()
 	return ct
 }
 
 type realContextTicker struct {
-	ctx context.Context
-	tkr *time.Ticker
-	f   func() error
-	err chan error
+	ctx      context.Context
+	tkr      *time.Ticker
+	f        func() error
+	err      chan error
+	stop     chan struct{}
+	stopOnce sync.Once
 }
 
 func (t *realContextTicker) Wait(_ ...string) error {
 	return <-t.err
 }
 
+func (t *realContextTicker) Stop(_ ...string) {
+	t.stopOnce.Do(func() {
+		close(t.stop)
+	})
+}
+
 func (t *realContextTicker) run() {
 	defer t.tkr.Stop()
 	for {
@@ -45,7 +57,17 @@ func (t *realContextTicker) run() {
 		case <-t.ctx.Done():
 			t.err <- t.ctx.Err()
 			return
+		case <-t.stop:
+			t.err <- ErrStopped
+			return
 		case <-t.tkr.C:
+			select {
+			case <-t.stop:
+				// don't call f if we were stopped while waiting
+				t.err <- ErrStopped
+				return
+			default:
+			}
 			err := t.f()
 			if err != nil {
 				t.err <- err
@@ -55,16 +77,178 @@ func (t *realContextTicker) run() {
 	}
 }
 
+func (realClock) AdaptiveTickerFunc(
+	ctx context.Context, interval func(n int, last error) time.Duration, f func() error, _ ...string,
+) StopWaiter {
+	t := &realAdaptiveTicker{
+		ctx:      ctx,
+		interval: interval,
+		f:        f,
+		err:      make(chan error, 1),
+		stop:     make(chan struct{}),
+	}
+	go t.run()
+	return t
+}
+
+type realAdaptiveTicker struct {
+	ctx      context.Context
+	interval func(n int, last error) time.Duration
+	f        func() error
+	err      chan error
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+func (t *realAdaptiveTicker) Wait(_ ...string) error {
+	return <-t.err
+}
+
+func (t *realAdaptiveTicker) Stop(_ ...string) {
+	t.stopOnce.Do(func() {
+		close(t.stop)
+	})
+}
+
+func (t *realAdaptiveTicker) run() {
+	var last error
+	for n := 0; ; n++ {
+		d := t.interval(n, last)
+		if d <= 0 {
+			t.err <- last
+			return
+		}
+		tmr := time.NewTimer(d)
+		select {
+		case <-t.ctx.Done():
+			tmr.Stop()
+			t.err <- t.ctx.Err()
+			return
+		case <-t.stop:
+			tmr.Stop()
+			t.err <- ErrStopped
+			return
+		case <-tmr.C:
+			select {
+			case <-t.stop:
+				// don't call f if we were stopped while waiting
+				t.err <- ErrStopped
+				return
+			default:
+			}
+			last = t.f()
+		}
+	}
+}
+
+func (realClock) TimerFunc(ctx context.Context, d time.Duration, f func() error, _ ...string) Waiter {
+	t := &realTimerFunc{done: make(chan struct{})}
+	go t.run(ctx, time.NewTimer(d), f)
+	return t
+}
+
+type realTimerFunc struct {
+	done chan struct{}
+	err  error
+}
+
+func (t *realTimerFunc) Wait(_ ...string) error {
+	<-t.done
+	return t.err
+}
+
+func (t *realTimerFunc) run(ctx context.Context, tmr *time.Timer, f func() error) {
+	defer close(t.done)
+	select {
+	case <-ctx.Done():
+		tmr.Stop()
+		t.err = ctx.Err()
+	case <-tmr.C:
+		t.err = f()
+	}
+}
+
 func (realClock) NewTimer(d time.Duration, _ ...string) *Timer {
 	rt := time.NewTimer(d)
 	return &Timer{C: rt.C, timer: rt}
 }
 
-func (realClock) AfterFunc(d time.Duration, f func(), _ ...string) *Timer {
+func (realClock) NewTimerInto(ch chan<- time.Time, d time.Duration, _ ...string) *Timer {
+	into := &realTimerInto{ch: ch, stop: make(chan struct{})}
+	rt := time.AfterFunc(d, into.send)
+	return &Timer{timer: rt, into: into}
+}
+
+// realTimerInto delivers to the channel passed to NewTimerInto. Like a Go 1.23 timer channel, the
+// send blocks until the channel is read, or the timer is stopped or reset.
+type realTimerInto struct {
+	ch   chan<- time.Time
+	mu   sync.Mutex
+	stop chan struct{}
+}
+
+func (r *realTimerInto) send() {
+	r.mu.Lock()
+	stop := r.stop
+	r.mu.Unlock()
+	select {
+	case r.ch <- time.Now():
+	case <-stop:
+	}
+}
+
+// interrupt abandons any pending send. It is a no-op on a nil realTimerInto, so that Timers not
+// created by NewTimerInto can call it unconditionally.
+func (r *realTimerInto) interrupt() {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	close(r.stop)
+	r.stop = make(chan struct{})
+}
+
+func (realClock) After(d time.Duration, _ ...string) <-chan time.Time {
+	return time.After(d)
+}
+
+func (realClock) Sleep(d time.Duration, _ ...string) {
+	time.Sleep(d)
+}
+
+func (realClock) SleepContext(ctx context.Context, d time.Duration, _ ...string) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	tmr := time.NewTimer(d)
+	defer tmr.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-tmr.C:
+		return nil
+	}
+}
+
+func (c realClock) AfterFunc(d time.Duration, f func(), _ ...string) *Timer {
+	if c.afterFuncExec != nil {
+		exec := c.afterFuncExec
+		g := f
+		f = func() { exec(g) }
+	}
 	rt := time.AfterFunc(d, f)
 	return &Timer{C: rt.C, timer: rt}
 }
 
+func (realClock) WithTimeout(ctx context.Context, d time.Duration, _ ...string) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, d)
+}
+
+func (realClock) WithDeadline(ctx context.Context, t time.Time, _ ...string) (context.Context, context.CancelFunc) {
+	return context.WithDeadline(ctx, t)
+}
+
 func (realClock) Now(_ ...string) time.Time {
 	return time.Now()
 }
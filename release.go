@@ -0,0 +1,139 @@
+package quartz
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// ReleaseAfter causes the trap to release the calls it catches automatically, once the clock has
+// been advanced by d after the call, rather than delivering them to Wait. This simulates slow
+// calls deterministically, e.g. a Now call that takes 10ms to return:
+//
+//	mClock.Trap().Now("slow").ReleaseAfter(10 * time.Millisecond)
+//
+// The release is scheduled as an event when the call is made, so it is visible to Peek, and the
+// AdvanceWaiter of the advance that reaches it waits for the call to complete. A d of zero or less
+// releases calls immediately. It returns the trap, and should be called before any calls are
+// caught.
+func (t *Trap) ReleaseAfter(d time.Duration) *Trap {
+	t.mock.mu.Lock()
+	defer t.mock.mu.Unlock()
+	t.releaseAfter = &d
+	return t
+}
+
+// AutoRelease causes the trap to release the first n calls it catches automatically, rather than
+// delivering them to Wait. This suits tests that only care that a call happened, and with which
+// arguments, not about gating its progress:
+//
+//	trap := mClock.Trap().NewTimer("retry").AutoRelease(3)
+//	// ... run the code under test ...
+//	for _, c := range trap.AutoReleased() {
+//		// assert on c.Duration
+//	}
+//
+// Calls after the first n are caught as usual. It returns the trap, and should be called before any
+// calls are caught.
+func (t *Trap) AutoRelease(n int) *Trap {
+	t.mock.mu.Lock()
+	defer t.mock.mu.Unlock()
+	t.autoRelease = n
+	return t
+}
+
+// AutoReleased returns the calls the trap has released automatically, see AutoRelease, in the
+// order they were made.
+func (t *Trap) AutoReleased() []*Call {
+	t.mock.mu.Lock()
+	defer t.mock.mu.Unlock()
+	return slices.Clone(t.autoReleased)
+}
+
+// AdvanceThenRelease advances the clock by d, as if by AdvanceTo, waits for the advance to complete, then
+// releases the call and waits for it to complete. This models the time the code under test spends
+// around the clock call, e.g. the latency of the request a trapped Now call times, in a single
+// operation. It returns an error if the context expires first.
+func (c *Call) AdvanceThenRelease(ctx context.Context, d time.Duration) error {
+	m := c.trap.mock
+	m.mu.Lock()
+	target := addDuration(m.cur, d)
+	m.mu.Unlock()
+	if err := m.AdvanceTo(target).Wait(ctx); err != nil {
+		return fmt.Errorf("advancing clock before release: %w", err)
+	}
+	return c.Release(ctx)
+}
+
+// MustAdvanceThenRelease calls AdvanceThenRelease, and fails the test immediately if there is an error.
+func (c *Call) MustAdvanceThenRelease(ctx context.Context, d time.Duration) {
+	if err := c.AdvanceThenRelease(ctx, d); err != nil {
+		c.tb.Helper()
+		c.tb.Fatal(err.Error())
+	}
+}
+
+// delayedRelease is the state of a call caught by a trap with ReleaseAfter.
+type delayedRelease struct {
+	// ready is closed when the delay has elapsed.
+	ready chan struct{}
+	// done is closed when the call has been released by the trap.
+	done chan struct{}
+}
+
+// scheduleReleaseLocked schedules the automatic release of the call by the trap, if it has a
+// release delay, or is still auto-releasing calls.
+func (t *Trap) scheduleReleaseLocked(c *apiCall) {
+	auto := len(t.autoReleased) < t.autoRelease
+	if t.releaseAfter == nil && !auto {
+		return
+	}
+	dr := &delayedRelease{ready: make(chan struct{}), done: make(chan struct{})}
+	if c.delayedReleases == nil {
+		c.delayedReleases = make(map[*Trap]*delayedRelease)
+	}
+	c.delayedReleases[t] = dr
+	if auto {
+		call := t.newCall(c)
+		call.released.Store(true)
+		t.autoReleased = append(t.autoReleased, call)
+		close(dr.ready)
+		return
+	}
+	d := *t.releaseAfter
+	if d <= 0 {
+		close(dr.ready)
+		return
+	}
+	m := t.mock
+	m.addEventLocked(&userEvent{
+		mock: m,
+		kind: "ReleaseAfter",
+		tags: c.Tags,
+		d:    d,
+		nxt:  addDuration(m.cur, d),
+		f: func() {
+			close(dr.ready)
+			<-dr.done
+		},
+	})
+}
+
+// releaseDelayed waits for the release delay of the call to elapse, then releases it and waits for
+// it to complete, or be passed to lower priority traps. Unlike calls released by the test, the call
+// is never counted as unreleased.
+func (t *Trap) releaseDelayed(a *apiCall, dr *delayedRelease) {
+	defer close(dr.done)
+	select {
+	case <-t.done:
+		a.releases.Done()
+		return
+	case <-dr.ready:
+	}
+	a.releases.Done()
+	select {
+	case <-a.complete:
+	case <-a.stageReleased:
+	}
+}
@@ -0,0 +1,37 @@
+package quartz
+
+import (
+	"time"
+)
+
+// WithTimeReport causes the Mock to log, at the end of the test, the total virtual time it
+// simulated and the real time the test took, along with the ratio between them. If the test is a
+// benchmark, the ratio is also reported as the "virtual-sec/real-sec" metric.
+//
+// This is useful for tracking the value of virtual-time tests, and spotting tests that spend most
+// of their time sleeping in real time despite using a Mock.
+func (m *Mock) WithTimeReport() *Mock {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.reportingTime {
+		return m
+	}
+	m.reportingTime = true
+	m.tb.Cleanup(func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		virtual, wall := m.cur.Sub(m.origin), time.Since(m.realStart)
+		ratio := float64(virtual) / float64(wall)
+		m.logfLocked("simulated %s of virtual time in %s of real time (%.1fx)",
+			virtual, wall, ratio)
+		if r, ok := m.tb.(metricReporter); ok {
+			r.ReportMetric(ratio, "virtual-sec/real-sec")
+		}
+	})
+	return m
+}
+
+// metricReporter is implemented by *testing.B.
+type metricReporter interface {
+	ReportMetric(n float64, unit string)
+}
@@ -0,0 +1,209 @@
+package quartz
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// NewScaledClock returns a Clock that runs scale times faster than clk, usually the real Clock: a
+// timer for d fires after d/scale on clk, and Now advances scale times faster than clk's Now,
+// starting from clk's current time. It allows workloads written in terms of realistic durations to
+// run on real time in a fraction of the time, e.g. for DualRun.
+//
+// Only durations passed to the Clock are scaled: durations passed to Reset on the returned Timers
+// and Tickers, and the times they deliver, are those of clk.
+//
+// The returned Clock can be paused with FreezeAll, once registered with RegisterFreezable. Timers
+// created by NewTimer, After, AfterFunc, Sleep, SleepContext, TimerFunc, TickerFunc and
+// AdaptiveTickerFunc are paused along with Now, but those created by NewTicker and NewTimerInto,
+// and the deadlines of WithTimeout and WithDeadline, keep running on clk.
+func NewScaledClock(clk Clock, scale float64) Clock {
+	if scale <= 0 {
+		panic("NewScaledClock called with non-positive scale")
+	}
+	s := &scaledClock{clk: clk, scale: scale, start: clk.Now()}
+	s.resumed = sync.NewCond(&s.mu)
+	return s
+}
+
+type scaledClock struct {
+	clk   Clock
+	scale float64
+	start time.Time
+
+	// mu protects the state of freezing, see FreezeAll.
+	mu sync.Mutex
+	// frozen is the number of nested freezes, frozenAt the time Now returns while frozen, and
+	// frozenSince the time on clk at which the freeze started.
+	frozen      int
+	frozenAt    time.Time
+	frozenSince time.Time
+	// paused is the total time on clk spent frozen, which does not count towards Now.
+	paused time.Duration
+	// resumed is broadcast when the last nested freeze ends.
+	resumed *sync.Cond
+}
+
+func (s *scaledClock) freeze() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.frozen == 0 {
+		s.frozenAt = s.nowLocked()
+		s.frozenSince = s.clk.Now()
+	}
+	s.frozen++
+}
+
+func (s *scaledClock) resume() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.frozen == 0 {
+		return
+	}
+	s.frozen--
+	if s.frozen == 0 {
+		s.paused += s.clk.Since(s.frozenSince)
+		s.resumed.Broadcast()
+	}
+}
+
+func (s *scaledClock) resumeAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.frozen == 0 {
+		return
+	}
+	s.frozen = 0
+	s.paused += s.clk.Since(s.frozenSince)
+	s.resumed.Broadcast()
+}
+
+// hold is called when a timer fires on clk. If the clock is frozen, it blocks until the clock
+// resumes, then for the time the timer had left when the freeze started, so that the timer fires
+// as if the pause never happened.
+func (s *scaledClock) hold() {
+	s.mu.Lock()
+	if s.frozen == 0 {
+		s.mu.Unlock()
+		return
+	}
+	left := s.clk.Since(s.frozenSince)
+	for s.frozen > 0 {
+		s.resumed.Wait()
+	}
+	s.mu.Unlock()
+	s.clk.Sleep(left)
+}
+
+// held wraps f to be called once the clock is not frozen, see hold.
+func (s *scaledClock) held(f func() error) func() error {
+	return func() error {
+		s.hold()
+		return f()
+	}
+}
+
+func (s *scaledClock) nowLocked(tags ...string) time.Time {
+	if s.frozen > 0 {
+		return s.frozenAt
+	}
+	elapsed := s.clk.Since(s.start, tags...) - s.paused
+	return s.start.Add(time.Duration(float64(elapsed) * s.scale))
+}
+
+// d scales the duration, keeping positive durations positive, since tickers panic on zero.
+func (s *scaledClock) d(d time.Duration) time.Duration {
+	if d <= 0 {
+		return d
+	}
+	return max(time.Duration(float64(d)/s.scale), 1)
+}
+
+func (s *scaledClock) NewTicker(d time.Duration, tags ...string) *Ticker {
+	return s.clk.NewTicker(s.d(d), tags...)
+}
+
+func (s *scaledClock) TickerFunc(ctx context.Context, d time.Duration, f func() error, tags ...string) StopWaiter {
+	return s.clk.TickerFunc(ctx, s.d(d), s.held(f), tags...)
+}
+
+func (s *scaledClock) AdaptiveTickerFunc(
+	ctx context.Context, interval func(n int, last error) time.Duration, f func() error, tags ...string,
+) StopWaiter {
+	return s.clk.AdaptiveTickerFunc(ctx, func(n int, last error) time.Duration {
+		return s.d(interval(n, last))
+	}, s.held(f), tags...)
+}
+
+func (s *scaledClock) TimerFunc(ctx context.Context, d time.Duration, f func() error, tags ...string) Waiter {
+	return s.clk.TimerFunc(ctx, s.d(d), s.held(f), tags...)
+}
+
+// NewTimer is an AfterFunc on clk delivering to C, so that delivery can be held while frozen. Like
+// a pre Go 1.23 timer channel, C has a buffer of one, and a time not yet read is not discarded by
+// Stop or Reset.
+func (s *scaledClock) NewTimer(d time.Duration, tags ...string) *Timer {
+	c := make(chan time.Time, 1)
+	t := s.clk.AfterFunc(s.d(d), func() {
+		s.hold()
+		select {
+		case c <- s.clk.Now():
+		default:
+		}
+	}, tags...)
+	t.C = c
+	return t
+}
+
+func (s *scaledClock) NewTimerInto(ch chan<- time.Time, d time.Duration, tags ...string) *Timer {
+	return s.clk.NewTimerInto(ch, s.d(d), tags...)
+}
+
+func (s *scaledClock) After(d time.Duration, tags ...string) <-chan time.Time {
+	return s.NewTimer(d, tags...).C
+}
+
+func (s *scaledClock) Sleep(d time.Duration, tags ...string) {
+	s.clk.Sleep(s.d(d), tags...)
+	s.hold()
+}
+
+func (s *scaledClock) SleepContext(ctx context.Context, d time.Duration, tags ...string) error {
+	if err := s.clk.SleepContext(ctx, s.d(d), tags...); err != nil {
+		return err
+	}
+	s.hold()
+	return nil
+}
+
+func (s *scaledClock) AfterFunc(d time.Duration, f func(), tags ...string) *Timer {
+	return s.clk.AfterFunc(s.d(d), func() {
+		s.hold()
+		f()
+	}, tags...)
+}
+
+func (s *scaledClock) WithTimeout(ctx context.Context, d time.Duration, tags ...string) (context.Context, context.CancelFunc) {
+	return s.clk.WithTimeout(ctx, s.d(d), tags...)
+}
+
+func (s *scaledClock) WithDeadline(ctx context.Context, t time.Time, tags ...string) (context.Context, context.CancelFunc) {
+	return s.clk.WithTimeout(ctx, s.d(t.Sub(s.Now())), tags...)
+}
+
+func (s *scaledClock) Now(tags ...string) time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.nowLocked(tags...)
+}
+
+func (s *scaledClock) Since(t time.Time, tags ...string) time.Duration {
+	return s.Now(tags...).Sub(t)
+}
+
+func (s *scaledClock) Until(t time.Time, tags ...string) time.Duration {
+	return t.Sub(s.Now(tags...))
+}
+
+var _ Clock = &scaledClock{}
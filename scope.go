@@ -0,0 +1,105 @@
+package quartz
+
+import (
+	"sync"
+	"time"
+)
+
+// Scope advances a Mock within a budget of virtual time, see Mock.Scope.
+type Scope struct {
+	mock   *Mock
+	parent *Scope
+	budget time.Duration
+
+	mu   sync.Mutex
+	used time.Duration
+}
+
+// Scope returns a Scope that advances the Mock by at most budget in total. Test helpers and shared
+// fixtures can take a Scope, rather than the Mock, to move time forward for their own setup, while
+// the test bounds how far they may go, e.g. to stay short of a deadline the test is waiting for.
+// An advance that would exceed the budget fails the test without advancing.
+//
+// Scopes nest: advances of a Scope created from another Scope also count against its parent's
+// budget.
+func (m *Mock) Scope(budget time.Duration) *Scope {
+	return &Scope{mock: m, budget: budget}
+}
+
+// Scope returns a child Scope with the given budget, whose advances also count against s.
+func (s *Scope) Scope(budget time.Duration) *Scope {
+	return &Scope{mock: s.mock, parent: s, budget: budget}
+}
+
+// Remaining returns how much the Scope, and every Scope it was created from, may still advance.
+func (s *Scope) Remaining() time.Duration {
+	s.mu.Lock()
+	r := s.budget - s.used
+	s.mu.Unlock()
+	if s.parent != nil {
+		r = min(r, s.parent.Remaining())
+	}
+	return r
+}
+
+// charge counts d against the Scope and its parents.
+func (s *Scope) charge(d time.Duration) {
+	for ; s != nil; s = s.parent {
+		s.mu.Lock()
+		s.used += d
+		s.mu.Unlock()
+	}
+}
+
+// allow fails the test and returns false if advancing by d would exceed the budget.
+func (s *Scope) allow(fn string, d time.Duration) bool {
+	s.mock.tb.Helper()
+	if r := s.Remaining(); d > r {
+		s.mock.tb.Errorf("cannot %s by %s, which exceeds the remaining budget %s of the Scope", fn, d, r)
+		return false
+	}
+	return true
+}
+
+// closedWaiter returns an AdvanceWaiter for an advance that didn't happen.
+func (s *Scope) closedWaiter() AdvanceWaiter {
+	w := newAdvanceWaiter(s.mock)
+	close(w.ch)
+	return w
+}
+
+// Advance is like Mock.Advance, but fails the test if d exceeds the remaining budget.
+func (s *Scope) Advance(d time.Duration) AdvanceWaiter {
+	s.mock.tb.Helper()
+	if !s.allow("Advance", d) {
+		return s.closedWaiter()
+	}
+	s.charge(d)
+	return s.mock.Advance(d)
+}
+
+// AdvanceTo is like Mock.AdvanceTo, but fails the test if target is further away than the remaining
+// budget.
+func (s *Scope) AdvanceTo(target time.Time) AdvanceWaiter {
+	s.mock.tb.Helper()
+	s.mock.mu.Lock()
+	d := target.Sub(s.mock.cur)
+	s.mock.mu.Unlock()
+	if !s.allow("AdvanceTo", d) {
+		return s.closedWaiter()
+	}
+	s.charge(max(d, 0))
+	return s.mock.AdvanceTo(target)
+}
+
+// AdvanceNext is like Mock.AdvanceNext, but fails the test if the next event is further away than
+// the remaining budget.
+func (s *Scope) AdvanceNext() (time.Duration, AdvanceWaiter) {
+	s.mock.tb.Helper()
+	if d, ok := s.mock.Peek(); ok && !s.allow("AdvanceNext", d) {
+		return 0, s.closedWaiter()
+	}
+	d, w := s.mock.AdvanceNext()
+	s.charge(d)
+	return d, w
+}
@@ -0,0 +1,160 @@
+// Package sim provides a discrete-event simulation API on top of the timeline of a quartz.Mock.
+// Events scheduled with a Sim run alongside the timers and tickers of code using the Mock as its
+// quartz.Clock, so capacity models and schedulers can be developed and validated against the same
+// Clock abstraction used in production and tests, in virtual time.
+package sim
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/coder/quartz"
+)
+
+// Sim is a discrete-event simulation driven by a quartz.Mock. It is safe for concurrent use.
+type Sim struct {
+	mock  *quartz.Mock
+	start time.Time
+
+	mu      sync.Mutex
+	fired   int
+	byTag   map[string]int
+	samples map[string]Summary
+}
+
+// New returns a Sim on m, starting at its current time. Only events fired by the Sim's Run and
+// RunUntil are counted in its Stats.
+func New(m *quartz.Mock) *Sim {
+	return &Sim{
+		mock:    m,
+		start:   m.Now(),
+		byTag:   make(map[string]int),
+		samples: make(map[string]Summary),
+	}
+}
+
+// Clock returns the Clock of the simulation, for the models and code under simulation to use.
+func (s *Sim) Clock() quartz.Clock {
+	return s.mock
+}
+
+// Now returns the current simulated time.
+func (s *Sim) Now() time.Time {
+	return s.mock.Now()
+}
+
+// Schedule schedules f to be called after d, which must be positive, of simulated time. Like
+// quartz.Mock.At, f is called on its own goroutine, and may schedule further events.
+func (s *Sim) Schedule(d time.Duration, f func(), tags ...string) {
+	s.mock.At(s.mock.Now().Add(d), f, tags...)
+}
+
+// ScheduleAt schedules f to be called at t, which must be after the current simulated time.
+func (s *Sim) ScheduleAt(t time.Time, f func(), tags ...string) {
+	s.mock.At(t, f, tags...)
+}
+
+// Run advances the simulation from event to event, waiting for each to complete, until no events
+// remain. Since running tickers never become quiescent, simulations with tickers should use
+// RunUntil instead. If ctx expires, it returns the context error.
+func (s *Sim) Run(ctx context.Context) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if _, ok := s.mock.Peek(); !ok {
+			return nil
+		}
+		_, _, w := s.mock.AdvanceNextEvents()
+		err := w.Wait(ctx)
+		s.record(w.Events())
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// RunUntil advances the simulation to t, firing each event up to t at its scheduled time, and waits
+// for them to complete. If ctx expires, it returns the context error.
+func (s *Sim) RunUntil(ctx context.Context, t time.Time) error {
+	w := s.mock.AdvanceTo(t)
+	err := w.Wait(ctx)
+	s.record(w.Events())
+	return err
+}
+
+func (s *Sim) record(events []quartz.EventInfo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range events {
+		s.fired++
+		for _, tag := range e.Tags {
+			s.byTag[tag]++
+		}
+	}
+}
+
+// Observe records the value v of the named statistic, e.g. the latency of a simulated request,
+// to be summarized in Stats.
+func (s *Sim) Observe(name string, v float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sum, ok := s.samples[name]
+	if !ok {
+		sum = Summary{Min: math.Inf(1), Max: math.Inf(-1)}
+	}
+	sum.Count++
+	sum.Sum += v
+	sum.Min = min(sum.Min, v)
+	sum.Max = max(sum.Max, v)
+	s.samples[name] = sum
+}
+
+// Stats are statistics collected by a Sim.
+type Stats struct {
+	// Elapsed is the simulated time since the Sim was created.
+	Elapsed time.Duration
+	// Fired is the number of events fired by Run and RunUntil, including timers and tickers of the
+	// code under simulation, and ByTag the number of them carrying each tag.
+	Fired int
+	ByTag map[string]int
+	// Samples summarizes the values recorded with Observe, by name.
+	Samples map[string]Summary
+}
+
+// Summary summarizes the values of a statistic.
+type Summary struct {
+	Count    int
+	Sum      float64
+	Min, Max float64
+}
+
+// Mean returns the mean of the values, or NaN if there are none.
+func (s Summary) Mean() float64 {
+	if s.Count == 0 {
+		return math.NaN()
+	}
+	return s.Sum / float64(s.Count)
+}
+
+// Stats returns the statistics collected so far.
+func (s *Sim) Stats() Stats {
+	now := s.mock.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := Stats{
+		Elapsed: now.Sub(s.start),
+		Fired:   s.fired,
+		ByTag:   make(map[string]int, len(s.byTag)),
+		Samples: make(map[string]Summary, len(s.samples)),
+	}
+	for k, v := range s.byTag {
+		st.ByTag[k] = v
+	}
+	for k, v := range s.samples {
+		st.Samples[k] = v
+	}
+	return st
+}
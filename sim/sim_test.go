@@ -0,0 +1,67 @@
+package sim_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/coder/quartz"
+	"github.com/coder/quartz/sim"
+)
+
+// TestSim models a single server taking 3s per request, with a request arriving every 2s.
+func TestSim(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	s := sim.New(quartz.NewMock(t))
+	start := s.Now()
+	var busyUntil time.Time
+	for i := 1; i <= 4; i++ {
+		arrival := start.Add(time.Duration(2*i) * time.Second)
+		s.ScheduleAt(arrival, func() {
+			begin := arrival
+			if busyUntil.After(begin) {
+				begin = busyUntil
+			}
+			busyUntil = begin.Add(3 * time.Second)
+			s.ScheduleAt(busyUntil, func() {
+				s.Observe("latency", s.Now().Sub(arrival).Seconds())
+			}, "done")
+		}, "arrival")
+	}
+	if err := s.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	st := s.Stats()
+	if st.Elapsed != 14*time.Second {
+		t.Errorf("expected the last request to complete after 14s, got %s", st.Elapsed)
+	}
+	if st.Fired != 8 || st.ByTag["arrival"] != 4 || st.ByTag["done"] != 4 {
+		t.Errorf("expected 4 arrivals and 4 completions, got %d: %v", st.Fired, st.ByTag)
+	}
+	// latencies are 3s, 4s, 5s and 6s, as the queue builds up.
+	lat := st.Samples["latency"]
+	if lat.Count != 4 || lat.Min != 3 || lat.Max != 6 || lat.Mean() != 4.5 {
+		t.Errorf("unexpected latency %+v", lat)
+	}
+}
+
+func TestSim_RunUntil(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mClock := quartz.NewMock(t)
+	s := sim.New(mClock)
+	tkr := s.Clock().NewTicker(time.Second, "tick")
+	defer tkr.Stop()
+	if err := s.RunUntil(ctx, s.Now().Add(5*time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if st := s.Stats(); st.Fired != 5 || st.ByTag["tick"] != 5 || st.Elapsed != 5*time.Second {
+		t.Errorf("expected 5 ticks in 5s, got %+v", st)
+	}
+}
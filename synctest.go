@@ -0,0 +1,21 @@
+//go:build go1.25
+
+package quartz
+
+import "testing/synctest"
+
+// WithSynctest causes the methods that advance the clock, like Advance, AdvanceTo and AdvanceNext,
+// to call synctest.Wait before returning, so they only return once every other goroutine in the
+// synctest bubble is durably blocked. Together with the events they fire, this includes the code
+// under test reacting to them, so tests rarely need to wait on the AdvanceWaiter, or trap calls
+// just to know the code under test has caught up.
+//
+// The Mock must be used from within a bubble, i.e. a synctest.Test, and only advanced from the
+// test's own goroutine, since synctest.Wait may not be called concurrently. Goroutines blocked on
+// a mutex or on I/O are not durably blocked, so Wait keeps waiting for them.
+func (m *Mock) WithSynctest() *Mock {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.quiesce = synctest.Wait
+	return m
+}
@@ -0,0 +1,40 @@
+//go:build go1.25
+
+package quartz_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/coder/quartz"
+)
+
+func TestWithSynctest(t *testing.T) {
+	t.Parallel()
+	synctest.Test(t, func(t *testing.T) {
+		mClock := quartz.NewMock(t).WithSynctest()
+		var ticks atomic.Int64
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		// the ticker func is started asynchronously, and the advances below don't wait on it, so
+		// without synctest, these would race with the code under test.
+		go mClock.TickerFunc(ctx, time.Second, func() error {
+			ticks.Add(1)
+			return nil
+		})
+		synctest.Wait()
+		for i := int64(1); i <= 3; i++ {
+			mClock.Advance(time.Second)
+			if n := ticks.Load(); n != i {
+				t.Fatalf("expected %d ticks, got %d", i, n)
+			}
+		}
+		mClock.AdvanceNext()
+		if n := ticks.Load(); n != 4 {
+			t.Fatalf("expected 4 ticks, got %d", n)
+		}
+	})
+}
@@ -0,0 +1,71 @@
+package quartz
+
+import "sync"
+
+var (
+	registeredTagsMu sync.Mutex
+	registeredTags   = make(map[string]bool)
+)
+
+// RegisterTag registers tag as a known tag, and returns it. It is intended for declaring the tag
+// vocabulary of a codebase as constants-like variables, so that tags are not misspelled:
+//
+//	var TagSyncLoop = quartz.RegisterTag("sync.loop")
+//
+// Mocks created with WithStrictTags reject tags that are not registered.
+func RegisterTag(tag string) string {
+	registeredTagsMu.Lock()
+	defer registeredTagsMu.Unlock()
+	registeredTags[tag] = true
+	return tag
+}
+
+func isRegisteredTag(tag string) bool {
+	registeredTagsMu.Lock()
+	defer registeredTagsMu.Unlock()
+	return registeredTags[tag]
+}
+
+// WithStrictTags causes the Mock to fail the test if a call or trap uses a tag that was not
+// registered with RegisterTag. This catches typos that would otherwise make traps silently miss the
+// calls they were meant to match. Tags derived from the context by WithContextTags are checked too.
+func (m *Mock) WithStrictTags() *Mock {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.strictTags = true
+	return m
+}
+
+// WithTagMapper causes the Mock to rewrite the tags of calls by the code under test with f, before
+// they are matched against traps, logged, and attached to the timers and tickers the calls create.
+// This decouples the tag conventions of third-party libraries from the expectations of the test,
+// e.g. by normalizing, prefixing or redacting tags:
+//
+//	mClock := quartz.NewMock(t).WithTagMapper(func(tags []string) []string {
+//		for i, tag := range tags {
+//			tags[i] = strings.ToLower(tag)
+//		}
+//		return tags
+//	})
+//
+// f is passed a copy of the tags, which it may modify. It is called with the Mock locked, so it
+// must not call the Mock. Tags derived from the context by WithContextTags are mapped too.
+func (m *Mock) WithTagMapper(f func(tags []string) []string) *Mock {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tagMapper = f
+	return m
+}
+
+// checkTagsLocked fails the test if the Mock has strict tags and any of tags is not registered.
+func (m *Mock) checkTagsLocked(what string, tags []string) {
+	if !m.strictTags {
+		return
+	}
+	for _, tag := range tags {
+		if !isRegisteredTag(tag) {
+			m.tb.Helper()
+			m.tb.Errorf("%s uses unregistered tag %q", what, tag)
+		}
+	}
+}
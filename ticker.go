@@ -1,18 +1,25 @@
 package quartz
 
-import "time"
+import (
+	"fmt"
+	"log/slog"
+	"time"
+)
 
 // A Ticker holds a channel that delivers “ticks” of a clock at intervals.
 type Ticker struct {
 	C <-chan time.Time
 	//nolint: revive
 	c             chan time.Time
-	ticker        *time.Ticker   // realtime impl, if set
-	d             time.Duration  // period, if set
-	nxt           time.Time      // next tick time
-	mock          *Mock          // mock clock, if set
-	stopped       bool           // true if the ticker is not running
-	internalTicks chan time.Time // used to deliver ticks to the runLoop goroutine
+	ticker        *time.Ticker  // realtime impl, if set
+	d             time.Duration // period, if set
+	nxt           time.Time     // next tick time
+	mock          *Mock         // mock clock, if set
+	stopped       bool          // true if the ticker is not running
+	internalTicks chan tick     // used to deliver ticks to the runLoop goroutine
+	droppedTicks  chan tick     // used to deliver ticks to the runLoop goroutine, while a tick is pending
+	tags          []string      // tags the ticker was created with
+	sync          bool          // true if fire waits for the tick to be received, see Mock.WithSyncTicks
 
 	// As of Go 1.23, ticker channels are unbuffered and guaranteed to block forever after a call to stop.
 	//
@@ -25,20 +32,46 @@ type Ticker struct {
 	// that we don't leak goroutines so that the garbage collector can do its job when the mock is no longer
 	// referenced. The channels below allow us to interrupt the runLoop goroutine.
 	interrupt chan struct{}
+	// query is used by Buffered to ask the runLoop goroutine whether it holds an unread tick.
+	query chan chan int
+}
+
+// tick is a tick delivered to the runLoop goroutine.
+type tick struct {
+	t time.Time
+	// delivered, if not nil, is closed once the tick is received from the channel, or the ticker
+	// is stopped.
+	delivered chan struct{}
 }
 
 func (t *Ticker) fire(tt time.Time) {
 	t.mock.mu.Lock()
-	defer t.mock.mu.Unlock()
 	if t.stopped {
+		t.mock.mu.Unlock()
 		return
 	}
 	for !t.nxt.After(t.mock.cur) {
-		t.nxt = t.nxt.Add(t.d)
+		t.nxt = addDuration(t.nxt, t.d)
 	}
 	t.mock.recomputeNextLocked()
-	if t.interrupt != nil { // implies runLoop is still going.
-		t.internalTicks <- tt
+	if t.interrupt == nil { // runLoop is not running.
+		t.mock.mu.Unlock()
+		return
+	}
+	t.mock.observeDeliveredLocked("NewTicker", tt)
+	tk := tick{t: tt}
+	if t.sync {
+		tk.delivered = make(chan struct{})
+	}
+	select {
+	case t.internalTicks <- tk:
+	case t.droppedTicks <- tk:
+		t.mock.dropTickLocked(t)
+		tk.delivered = nil
+	}
+	t.mock.mu.Unlock()
+	if tk.delivered != nil {
+		<-tk.delivered
 	}
 }
 
@@ -46,6 +79,10 @@ func (t *Ticker) next() time.Time {
 	return t.nxt
 }
 
+func (t *Ticker) info() EventInfo {
+	return EventInfo{Kind: clockFunctionNewTicker.String(), Tags: t.tags, Deadline: t.nxt, Duration: t.d}
+}
+
 // Stop turns off a ticker. After Stop, no more ticks will be sent. Stop does
 // not close the channel, to prevent a concurrent goroutine reading from the
 // channel from seeing an erroneous "tick".
@@ -59,6 +96,11 @@ func (t *Ticker) Stop(tags ...string) {
 	c := newCall(clockFunctionTickerStop, tags)
 	t.mock.matchCallLocked(c)
 	defer close(c.complete)
+	t.recordLocked(clockFunctionTickerStop, t.d, 0)
+	t.stopLocked()
+}
+
+func (t *Ticker) stopLocked() {
 	t.mock.removeEventLocked(t)
 	t.stopped = true
 	// check if we've already fired, and if so, interrupt it.
@@ -81,7 +123,9 @@ func (t *Ticker) Reset(d time.Duration, tags ...string) {
 	c := newCall(clockFunctionTickerReset, tags, withDuration(d))
 	t.mock.matchCallLocked(c)
 	defer close(c.complete)
-	t.nxt = t.mock.cur.Add(d)
+	d = t.mock.scheduleDurationLocked(c)
+	t.recordLocked(clockFunctionTickerReset, t.d, d)
+	t.nxt = addDuration(t.mock.cur, d)
 	t.d = d
 	if t.stopped {
 		t.stopped = false
@@ -99,17 +143,27 @@ func (t *Ticker) runLoop(interrupt chan struct{}) {
 outer:
 	for {
 		select {
-		case tt := <-t.internalTicks:
+		case tk := <-t.internalTicks:
 			for {
 				select {
-				case t.c <- tt:
+				case r := <-t.query:
+					r <- 1
+				case t.c <- tk.t:
+					if tk.delivered != nil {
+						close(tk.delivered)
+					}
 					continue outer
-				case <-t.internalTicks:
+				case <-t.droppedTicks:
 					// Discard future ticks until we can send this one.
 				case interrupt <- struct{}{}:
+					if tk.delivered != nil {
+						close(tk.delivered)
+					}
 					return
 				}
 			}
+		case r := <-t.query:
+			r <- 0
 		case interrupt <- struct{}{}:
 			return
 		}
@@ -126,16 +180,20 @@ func (t *Ticker) startRunLoopLocked() {
 	go t.runLoop(interrupt)
 }
 
-func newMockTickerLocked(m *Mock, d time.Duration) *Ticker {
+func newMockTickerLocked(m *Mock, d time.Duration, tags []string) *Ticker {
 	// no buffer follows Go 1.23+ behavior
 	ticks := make(chan time.Time)
 	t := &Ticker{
 		C:             ticks,
 		c:             ticks,
 		d:             d,
-		nxt:           m.cur.Add(d),
+		nxt:           addDuration(m.cur, d),
 		mock:          m,
-		internalTicks: make(chan time.Time),
+		internalTicks: make(chan tick),
+		droppedTicks:  make(chan tick),
+		query:         make(chan chan int),
+		tags:          tags,
+		sync:          m.syncTicksLocked(tags),
 	}
 	m.addEventLocked(t)
 	m.tb.Cleanup(func() {
@@ -149,3 +207,43 @@ func newMockTickerLocked(m *Mock, d time.Duration) *Ticker {
 	t.startRunLoopLocked()
 	return t
 }
+
+// Buffered returns the number of ticks sent on C that have not yet been received, which is at
+// most one, since like Go 1.23 tickers, the channel is unbuffered and ticks are dropped while one
+// is unread. Once the AdvanceWaiter of the advance that fired a tick is done, the tick is counted
+// until it is received, so tests can assert that exactly one tick is waiting without racing the
+// delivery. It always returns 0 for real tickers.
+func (t *Ticker) Buffered() int {
+	if t.mock == nil {
+		return 0
+	}
+	t.mock.mu.Lock()
+	defer t.mock.mu.Unlock()
+	if t.interrupt == nil { // runLoop is not running
+		return 0
+	}
+	r := make(chan int)
+	t.query <- r
+	return <-r
+}
+
+// String describes the ticker, including how it was created, its next tick and whether it is
+// stopped.
+func (t *Ticker) String() string {
+	if t.mock == nil {
+		return "Ticker(real)"
+	}
+	t.mock.mu.Lock()
+	defer t.mock.mu.Unlock()
+	return fmt.Sprintf("Ticker(%s, %s)", t.info(), timerState(t.stopped))
+}
+
+// LogValue implements slog.LogValuer.
+func (t *Ticker) LogValue() slog.Value {
+	if t.mock == nil {
+		return slog.StringValue("real")
+	}
+	t.mock.mu.Lock()
+	defer t.mock.mu.Unlock()
+	return eventLogValue(t.info(), timerState(t.stopped))
+}
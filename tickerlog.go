@@ -0,0 +1,53 @@
+package quartz
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// TickerEvent records the creation or reconfiguration of a Ticker created with NewTicker on a Mock.
+type TickerEvent struct {
+	// Kind is the name of the operation: "NewTicker", "Ticker.Reset" or "Ticker.Stop".
+	Kind string
+	// Tags are the tags the ticker was created with.
+	Tags []string
+	// Time is the time on the Mock at which the operation happened.
+	Time time.Time
+	// OldPeriod is the period of the ticker before the operation, or zero for NewTicker, and
+	// NewPeriod the period after it, or zero for Ticker.Stop.
+	OldPeriod time.Duration
+	NewPeriod time.Duration
+}
+
+func (e TickerEvent) String() string {
+	return fmt.Sprintf("%s(%v) at %s: %s -> %s",
+		e.Kind, e.Tags, e.Time.Format(time.RFC3339Nano), e.OldPeriod, e.NewPeriod)
+}
+
+// TickerEvents returns the creations, resets and stops of Tickers on the Mock, in order, for the
+// tickers whose tags include all the given tags. It allows assertions about how the code under
+// test reconfigures its tickers, e.g. that the period halves under load, to be made after the fact
+// rather than by trapping every call.
+func (m *Mock) TickerEvents(tags ...string) []TickerEvent {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var events []TickerEvent
+	for _, e := range m.tickerEvents {
+		if containsAll(e.Tags, tags) {
+			events = append(events, e)
+		}
+	}
+	return events
+}
+
+// recordLocked records a ticker operation, for TickerEvents.
+func (t *Ticker) recordLocked(fn clockFunction, oldPeriod, newPeriod time.Duration) {
+	t.mock.tickerEvents = append(t.mock.tickerEvents, TickerEvent{
+		Kind:      fn.String(),
+		Tags:      slices.Clone(t.tags),
+		Time:      t.mock.cur,
+		OldPeriod: oldPeriod,
+		NewPeriod: newPeriod,
+	})
+}
@@ -0,0 +1,53 @@
+package quartz
+
+import (
+	"strings"
+)
+
+// timelineSize is the number of recent log messages a Mock keeps to dump on test failure.
+const timelineSize = 100
+
+// timeline is a ring buffer of the most recent log messages of a Mock.
+type timeline struct {
+	msgs []string
+	next int
+}
+
+func (l *timeline) add(msg string) {
+	if len(l.msgs) < timelineSize {
+		l.msgs = append(l.msgs, msg)
+		return
+	}
+	l.msgs[l.next] = msg
+	l.next = (l.next + 1) % timelineSize
+}
+
+// all returns the messages, oldest first.
+func (l *timeline) all() []string {
+	return append(l.msgs[l.next:len(l.msgs):len(l.msgs)], l.msgs[:l.next]...)
+}
+
+// dumpTimelineLocked logs the recent log messages of the Mock and its pending events to the TB, to
+// make test failures involving the Mock self-explanatory. The recent messages are omitted if they
+// were already logged to the TB.
+func (m *Mock) dumpTimelineLocked() {
+	var b strings.Builder
+	b.WriteString("Mock Clock - test failed at ")
+	b.WriteString(m.cur.String())
+	if m.customLogger {
+		b.WriteString("\nrecent clock events:")
+		for _, msg := range m.history.all() {
+			b.WriteString("\n\t")
+			b.WriteString(msg)
+		}
+	}
+	b.WriteString("\npending events:")
+	if len(m.all) == 0 {
+		b.WriteString(" none")
+	}
+	for _, e := range m.all {
+		b.WriteString("\n\t")
+		b.WriteString(e.info().String())
+	}
+	m.tb.Log(b.String())
+}
@@ -0,0 +1,63 @@
+package quartz_test
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coder/quartz"
+)
+
+// cleanupTB is a testing.TB that captures logs and cleanup functions, so that tests can check what
+// is logged during cleanup.
+type cleanupTB struct {
+	captureFailTB
+	logs     []string
+	cleanups []func()
+}
+
+func (t *cleanupTB) Log(args ...any) {
+	t.logs = append(t.logs, fmt.Sprint(args...))
+}
+
+func (t *cleanupTB) Logf(format string, args ...any) {
+	t.logs = append(t.logs, fmt.Sprintf(format, args...))
+}
+
+func (t *cleanupTB) Cleanup(f func()) {
+	t.cleanups = append(t.cleanups, f)
+}
+
+func (t *cleanupTB) runCleanups() {
+	for _, f := range slices.Backward(t.cleanups) {
+		f()
+	}
+}
+
+func TestTimelineDumpOnFailure(t *testing.T) {
+	t.Parallel()
+	tb := &cleanupTB{captureFailTB: captureFailTB{TB: t}}
+	mClock := quartz.NewMock(tb).WithLogger(quartz.NoOpLogger)
+	mClock.NewTimer(time.Minute, "pending")
+	mClock.Advance(time.Second)
+	tb.Error("something went wrong")
+	tb.runCleanups()
+
+	var dump string
+	for _, l := range tb.logs {
+		if strings.HasPrefix(l, "Mock Clock - test failed") {
+			dump = l
+		}
+	}
+	for _, want := range []string{
+		"] NewTimer(1m0s, [pending]) call, matched 0 traps",
+		"] Advance(1s)",
+		"pending events:\n\tNewTimer(1m0s, [pending]) at 2024-01-01 00:01:00 +0000 UTC",
+	} {
+		if !strings.Contains(dump, want) {
+			t.Errorf("expected dump to contain %q, got %q", want, dump)
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package quartz
 
 import (
+	"fmt"
+	"log/slog"
 	"time"
 )
 
@@ -10,12 +12,18 @@ import (
 type Timer struct {
 	C <-chan time.Time
 	//nolint: revive
-	c       chan time.Time
-	timer   *time.Timer // realtime impl, if set
-	nxt     time.Time   // next tick time
-	mock    *Mock       // mock clock, if set
-	fn      func()      // AfterFunc function, if set
-	stopped bool        // True if stopped, false if running
+	c       chan<- time.Time
+	timer   *time.Timer    // realtime impl, if set
+	into    *realTimerInto // realtime impl of NewTimerInto, if set
+	nxt     time.Time      // next tick time
+	mock    *Mock          // mock clock, if set
+	fn      func()         // AfterFunc function, if set
+	stopped bool           // True if stopped, false if running
+
+	kind   clockFunction // the mock Clock method that created the timer
+	tags   []string      // tags the timer was created with
+	d      time.Duration // duration the timer was last set with
+	counts *TimerStats   // see Mock.TimerStats, nil for internal timers
 
 	// As of Go 1.23, timer channels are unbuffered and guaranteed to block forever after a call to stop.
 	//
@@ -28,16 +36,25 @@ type Timer struct {
 	// that we don't leak goroutines so that the garbage collector can do its job when the mock is no longer
 	// referenced. The channels below allow us to interrupt the channel write goroutine.
 	interrupt chan struct{}
+	// query is used by Buffered to ask the goroutine delivering the fired time whether it still
+	// holds it, and exited is closed once that goroutine exits.
+	query  chan chan int
+	exited chan struct{}
 }
 
 func (t *Timer) fire(tt time.Time) {
 	t.mock.mu.Lock()
 	t.mock.removeTimerLocked(t)
+	if t.counts != nil {
+		t.counts.Fired++
+	}
 	if t.fn != nil {
+		exec := t.mock.afterFuncExec
 		t.mock.mu.Unlock()
-		t.fn()
+		execAfterFunc(exec, t.fn)
 		return
 	} else {
+		t.mock.observeDeliveredLocked(t.kind.String(), tt)
 		interrupt := make(chan struct{})
 		// Prevents the goroutine from leaking beyond the test. Side effect is that timer channels cannot be read
 		// after the test exits.
@@ -45,12 +62,21 @@ func (t *Timer) fire(tt time.Time) {
 			<-interrupt
 		})
 		t.interrupt = interrupt
+		query, exited := make(chan chan int), make(chan struct{})
+		t.query, t.exited = query, exited
 		t.mock.mu.Unlock()
 		go func() {
 			defer close(interrupt)
-			select {
-			case t.c <- tt:
-			case interrupt <- struct{}{}:
+			defer close(exited)
+			for {
+				select {
+				case r := <-query:
+					r <- 1
+				case t.c <- tt:
+					return
+				case interrupt <- struct{}{}:
+					return
+				}
 			}
 		}()
 	}
@@ -60,6 +86,10 @@ func (t *Timer) next() time.Time {
 	return t.nxt
 }
 
+func (t *Timer) info() EventInfo {
+	return EventInfo{Kind: t.kind.String(), Tags: t.tags, Deadline: t.nxt, Duration: t.d}
+}
+
 // Stop prevents the Timer from firing. It returns true if the call stops the timer, false if the
 // timer has already expired or been stopped. Stop does not close the channel, to prevent a read
 // from the channel succeeding incorrectly.
@@ -67,6 +97,7 @@ func (t *Timer) next() time.Time {
 // See https://pkg.go.dev/time#Timer.Stop for more information.
 func (t *Timer) Stop(tags ...string) bool {
 	if t.timer != nil {
+		t.into.interrupt()
 		return t.timer.Stop()
 	}
 	t.mock.mu.Lock()
@@ -75,6 +106,9 @@ func (t *Timer) Stop(tags ...string) bool {
 	t.mock.matchCallLocked(c)
 	defer close(c.complete)
 	result := !t.stopped
+	if result && t.counts != nil {
+		t.counts.Stopped++
+	}
 	t.mock.removeTimerLocked(t)
 	// check if we've already fired, and if so, interrupt it.
 	if t.interrupt != nil {
@@ -90,6 +124,7 @@ func (t *Timer) Stop(tags ...string) bool {
 // See https://pkg.go.dev/time#Timer.Reset for more information.
 func (t *Timer) Reset(d time.Duration, tags ...string) bool {
 	if t.timer != nil {
+		t.into.interrupt()
 		return t.timer.Reset(d)
 	}
 	t.mock.mu.Lock()
@@ -97,6 +132,9 @@ func (t *Timer) Reset(d time.Duration, tags ...string) bool {
 	c := newCall(clockFunctionTimerReset, tags, withDuration(d))
 	t.mock.matchCallLocked(c)
 	defer close(c.complete)
+	if t.counts != nil {
+		t.counts.Reset++
+	}
 	result := !t.stopped
 	// check if we've already fired, and if so, interrupt it.
 	if t.interrupt != nil {
@@ -107,12 +145,74 @@ func (t *Timer) Reset(d time.Duration, tags ...string) bool {
 		// zero or negative duration timer means we should immediately re-fire
 		// it, rather than remove and re-add it.
 		t.stopped = false
-		go t.fire(t.mock.cur)
+		t.nxt = t.mock.cur
+		t.mock.goFireLocked(t, t.nxt)
 		return result
 	}
 	t.mock.removeTimerLocked(t)
 	t.stopped = false
-	t.nxt = t.mock.cur.Add(d)
+	t.d = d
+	t.nxt = addDuration(t.mock.cur, t.mock.scheduleDurationLocked(c))
 	t.mock.addEventLocked(t)
 	return result
 }
+
+// Buffered returns 1 if the timer has fired and the time sent on its channel has not yet been
+// received, and 0 otherwise. Once the AdvanceWaiter of the advance that fired the timer is done,
+// the time is counted until it is received, so tests can assert on it without racing the delivery.
+// It always returns 0 for real timers, and timers created by AfterFunc.
+func (t *Timer) Buffered() int {
+	if t.mock == nil {
+		return 0
+	}
+	t.mock.mu.Lock()
+	defer t.mock.mu.Unlock()
+	if t.interrupt == nil {
+		return 0
+	}
+	r := make(chan int)
+	select {
+	case t.query <- r:
+		return <-r
+	case <-t.exited:
+		return 0
+	}
+}
+
+// String describes the timer, including how it was created, its deadline and whether it is
+// stopped.
+func (t *Timer) String() string {
+	if t.mock == nil {
+		return "Timer(real)"
+	}
+	t.mock.mu.Lock()
+	defer t.mock.mu.Unlock()
+	return fmt.Sprintf("Timer(%s, %s)", t.info(), timerState(t.stopped))
+}
+
+// LogValue implements slog.LogValuer.
+func (t *Timer) LogValue() slog.Value {
+	if t.mock == nil {
+		return slog.StringValue("real")
+	}
+	t.mock.mu.Lock()
+	defer t.mock.mu.Unlock()
+	return eventLogValue(t.info(), timerState(t.stopped))
+}
+
+func timerState(stopped bool) string {
+	if stopped {
+		return "stopped"
+	}
+	return "active"
+}
+
+func eventLogValue(info EventInfo, state string) slog.Value {
+	return slog.GroupValue(
+		slog.String("kind", info.Kind),
+		slog.Any("tags", info.Tags),
+		slog.Duration("duration", info.Duration),
+		slog.Time("deadline", info.Deadline),
+		slog.String("state", state),
+	)
+}
@@ -0,0 +1,63 @@
+package quartz
+
+import (
+	"context"
+	"reflect"
+)
+
+// TrapGroup waits for calls caught by any of a set of traps, like a select statement. It is useful
+// when the code under test makes one of several clock calls, in an order that is not
+// deterministic.
+type TrapGroup struct {
+	traps []*Trap
+}
+
+// NewTrapGroup returns a TrapGroup of the given traps, which must belong to the same Mock. The
+// traps' calls should be consumed only through the group while it is in use, not with their own
+// Wait, Notify or Handle.
+func NewTrapGroup(traps ...*Trap) *TrapGroup {
+	return &TrapGroup{traps: traps}
+}
+
+// Wait waits for any of the traps to catch a call, and returns the call and the trap that caught
+// it. The call must be released as usual. If every trap is closed, it returns ErrTrapClosed.
+func (g *TrapGroup) Wait(ctx context.Context) (*Call, *Trap, error) {
+	cases := []reflect.SelectCase{{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())}}
+	for _, t := range g.traps {
+		cases = append(cases,
+			reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(t.calls)},
+			reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(t.done)},
+		)
+	}
+	open := len(g.traps)
+	for open > 0 {
+		i, v, _ := reflect.Select(cases)
+		if i == 0 {
+			return nil, nil, ctx.Err()
+		}
+		t := g.traps[(i-1)/2]
+		if (i-1)%2 == 1 {
+			// the trap is closed; stop selecting on it.
+			cases[i].Chan = reflect.Value{}
+			cases[i-1].Chan = reflect.Value{}
+			open--
+			continue
+		}
+		return t.receive(v.Interface().(*apiCall)), t, nil
+	}
+	return nil, nil, ErrTrapClosed
+}
+
+// MustWait calls Wait, and fails the test immediately if there is an error.
+func (g *TrapGroup) MustWait(ctx context.Context) (*Call, *Trap) {
+	if len(g.traps) == 0 {
+		panic("MustWait called on an empty TrapGroup")
+	}
+	tb := g.traps[0].mock.tb
+	tb.Helper()
+	c, t, err := g.Wait(ctx)
+	if err != nil {
+		tb.Fatalf("context expired while waiting for %d traps: %s", len(g.traps), err)
+	}
+	return c, t
+}
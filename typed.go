@@ -0,0 +1,240 @@
+package quartz
+
+import (
+	"context"
+	"time"
+)
+
+// TypedTrap is a Trap that yields calls of type C, which only have the fields that are meaningful
+// for the trapped method, so that test bugs like reading the Duration of a trapped Now call are
+// caught at compile time. Create them with the Trap* functions, e.g.
+//
+//	trap := quartz.TrapNewTimer(mClock.Trap(), "mytag")
+//	defer trap.Close()
+//	c := trap.MustWait(ctx)
+//	// c.Duration is the duration passed to NewTimer; c has no Time field.
+//	c.MustRelease(ctx)
+type TypedTrap[C any] struct {
+	trap    *Trap
+	convert func(*Call) C
+}
+
+func newTypedTrap[C any](trap *Trap, convert func(*Call) C) *TypedTrap[C] {
+	return &TypedTrap[C]{trap: trap, convert: convert}
+}
+
+// Wait for a call to be trapped, as for Trap.Wait.
+func (t *TypedTrap[C]) Wait(ctx context.Context) (C, error) {
+	c, err := t.trap.Wait(ctx)
+	if err != nil {
+		var zero C
+		return zero, err
+	}
+	return t.convert(c), nil
+}
+
+// MustWait calls Wait() and then if there is an error, immediately fails the test via tb.Fatalf()
+func (t *TypedTrap[C]) MustWait(ctx context.Context) C {
+	t.trap.mock.tb.Helper()
+	return t.convert(t.trap.MustWait(ctx))
+}
+
+// Close the trap, as for Trap.Close.
+func (t *TypedTrap[C]) Close() {
+	t.trap.mock.tb.Helper()
+	t.trap.Close()
+}
+
+// Trap returns the underlying untyped Trap.
+func (t *TypedTrap[C]) Trap() *Trap {
+	return t.trap
+}
+
+// typedCall holds the methods common to all typed calls.
+type typedCall struct {
+	call *Call
+}
+
+// Release the call and wait for it to complete, as for Call.Release.
+func (c typedCall) Release(ctx context.Context) error {
+	return c.call.Release(ctx)
+}
+
+// MustRelease releases the call and waits for it to complete, as for Call.MustRelease.
+func (c typedCall) MustRelease(ctx context.Context) {
+	c.call.tb.Helper()
+	c.call.MustRelease(ctx)
+}
+
+// Call returns the underlying untyped Call, e.g. to Cancel it or WrapFunc.
+func (c typedCall) Call() *Call {
+	return c.call
+}
+
+// TagsCall is a trapped call to a method that takes only tags, like Now or Timer.Stop.
+type TagsCall struct {
+	typedCall
+	Tags []string
+}
+
+// DurationCall is a trapped call to a method that takes a duration, like NewTimer or Ticker.Reset.
+type DurationCall struct {
+	typedCall
+	Duration time.Duration
+	Tags     []string
+}
+
+// TimeCall is a trapped call to a method that takes a time, like Since or Until.
+type TimeCall struct {
+	typedCall
+	Time time.Time
+	Tags []string
+}
+
+func toTagsCall(c *Call) TagsCall {
+	return TagsCall{typedCall: typedCall{c}, Tags: c.Tags}
+}
+
+func toDurationCall(c *Call) DurationCall {
+	return DurationCall{typedCall: typedCall{c}, Duration: c.Duration, Tags: c.Tags}
+}
+
+func toTimeCall(c *Call) TimeCall {
+	return TimeCall{typedCall: typedCall{c}, Time: c.Time, Tags: c.Tags}
+}
+
+// TrapNewTimer is a typed version of Trapper.NewTimer.
+func TrapNewTimer(t Trapper, tags ...string) *TypedTrap[DurationCall] {
+	return newTypedTrap(t.NewTimer(tags...), toDurationCall)
+}
+
+// TrapAfter is a typed version of Trapper.After.
+func TrapAfter(t Trapper, tags ...string) *TypedTrap[DurationCall] {
+	return newTypedTrap(t.After(tags...), toDurationCall)
+}
+
+// TrapSleep is a typed version of Trapper.Sleep.
+func TrapSleep(t Trapper, tags ...string) *TypedTrap[DurationCall] {
+	return newTypedTrap(t.Sleep(tags...), toDurationCall)
+}
+
+// TrapSleepContext is a typed version of Trapper.SleepContext.
+func TrapSleepContext(t Trapper, tags ...string) *TypedTrap[DurationCall] {
+	return newTypedTrap(t.SleepContext(tags...), toDurationCall)
+}
+
+// TrapWithTimeout is a typed version of Trapper.WithTimeout.
+func TrapWithTimeout(t Trapper, tags ...string) *TypedTrap[DurationCall] {
+	return newTypedTrap(t.WithTimeout(tags...), toDurationCall)
+}
+
+// TrapWithDeadline is a typed version of Trapper.WithDeadline.
+func TrapWithDeadline(t Trapper, tags ...string) *TypedTrap[TimeCall] {
+	return newTypedTrap(t.WithDeadline(tags...), toTimeCall)
+}
+
+// TrapNewTimerInto is a typed version of Trapper.NewTimerInto.
+func TrapNewTimerInto(t Trapper, tags ...string) *TypedTrap[DurationCall] {
+	return newTypedTrap(t.NewTimerInto(tags...), toDurationCall)
+}
+
+// TrapAfterFunc is a typed version of Trapper.AfterFunc.
+func TrapAfterFunc(t Trapper, tags ...string) *TypedTrap[DurationCall] {
+	return newTypedTrap(t.AfterFunc(tags...), toDurationCall)
+}
+
+// TrapTimerStop is a typed version of Trapper.TimerStop.
+func TrapTimerStop(t Trapper, tags ...string) *TypedTrap[TagsCall] {
+	return newTypedTrap(t.TimerStop(tags...), toTagsCall)
+}
+
+// TrapTimerReset is a typed version of Trapper.TimerReset.
+func TrapTimerReset(t Trapper, tags ...string) *TypedTrap[DurationCall] {
+	return newTypedTrap(t.TimerReset(tags...), toDurationCall)
+}
+
+// TrapTickerFunc is a typed version of Trapper.TickerFunc.
+func TrapTickerFunc(t Trapper, tags ...string) *TypedTrap[DurationCall] {
+	return newTypedTrap(t.TickerFunc(tags...), toDurationCall)
+}
+
+// TrapTickerFuncWait is a typed version of Trapper.TickerFuncWait.
+func TrapTickerFuncWait(t Trapper, tags ...string) *TypedTrap[TagsCall] {
+	return newTypedTrap(t.TickerFuncWait(tags...), toTagsCall)
+}
+
+// TrapTickerFuncStop is a typed version of Trapper.TickerFuncStop.
+func TrapTickerFuncStop(t Trapper, tags ...string) *TypedTrap[TagsCall] {
+	return newTypedTrap(t.TickerFuncStop(tags...), toTagsCall)
+}
+
+// TrapTimerFunc is a typed version of Trapper.TimerFunc.
+func TrapTimerFunc(t Trapper, tags ...string) *TypedTrap[DurationCall] {
+	return newTypedTrap(t.TimerFunc(tags...), toDurationCall)
+}
+
+// TrapTimerFuncWait is a typed version of Trapper.TimerFuncWait.
+func TrapTimerFuncWait(t Trapper, tags ...string) *TypedTrap[TagsCall] {
+	return newTypedTrap(t.TimerFuncWait(tags...), toTagsCall)
+}
+
+// TrapAdaptiveTickerFunc is a typed version of Trapper.AdaptiveTickerFunc.
+func TrapAdaptiveTickerFunc(t Trapper, tags ...string) *TypedTrap[DurationCall] {
+	return newTypedTrap(t.AdaptiveTickerFunc(tags...), toDurationCall)
+}
+
+// TrapAdaptiveTickerFuncWait is a typed version of Trapper.AdaptiveTickerFuncWait.
+func TrapAdaptiveTickerFuncWait(t Trapper, tags ...string) *TypedTrap[TagsCall] {
+	return newTypedTrap(t.AdaptiveTickerFuncWait(tags...), toTagsCall)
+}
+
+// TrapAdaptiveTickerFuncStop is a typed version of Trapper.AdaptiveTickerFuncStop.
+func TrapAdaptiveTickerFuncStop(t Trapper, tags ...string) *TypedTrap[TagsCall] {
+	return newTypedTrap(t.AdaptiveTickerFuncStop(tags...), toTagsCall)
+}
+
+// TrapNewTicker is a typed version of Trapper.NewTicker.
+func TrapNewTicker(t Trapper, tags ...string) *TypedTrap[DurationCall] {
+	return newTypedTrap(t.NewTicker(tags...), toDurationCall)
+}
+
+// TrapTickerStop is a typed version of Trapper.TickerStop.
+func TrapTickerStop(t Trapper, tags ...string) *TypedTrap[TagsCall] {
+	return newTypedTrap(t.TickerStop(tags...), toTagsCall)
+}
+
+// TrapTickerReset is a typed version of Trapper.TickerReset.
+func TrapTickerReset(t Trapper, tags ...string) *TypedTrap[DurationCall] {
+	return newTypedTrap(t.TickerReset(tags...), toDurationCall)
+}
+
+// TrapNow is a typed version of Trapper.Now.
+func TrapNow(t Trapper, tags ...string) *TypedTrap[TagsCall] {
+	return newTypedTrap(t.Now(tags...), toTagsCall)
+}
+
+// TrapSince is a typed version of Trapper.Since.
+func TrapSince(t Trapper, tags ...string) *TypedTrap[TimeCall] {
+	return newTypedTrap(t.Since(tags...), toTimeCall)
+}
+
+// TrapUntil is a typed version of Trapper.Until.
+func TrapUntil(t Trapper, tags ...string) *TypedTrap[TimeCall] {
+	return newTypedTrap(t.Until(tags...), toTimeCall)
+}
+
+// TrapAdvance is a typed version of Trapper.Advance, for testing harnesses. The calls have no tags.
+func TrapAdvance(t Trapper) *TypedTrap[DurationCall] {
+	return newTypedTrap(t.Advance(), toDurationCall)
+}
+
+// TrapAdvanceNext is a typed version of Trapper.AdvanceNext, for testing harnesses. The calls have
+// no tags.
+func TrapAdvanceNext(t Trapper) *TypedTrap[TagsCall] {
+	return newTypedTrap(t.AdvanceNext(), toTagsCall)
+}
+
+// TrapSet is a typed version of Trapper.Set, for testing harnesses. The calls have no tags.
+func TrapSet(t Trapper) *TypedTrap[TimeCall] {
+	return newTypedTrap(t.Set(), toTimeCall)
+}
@@ -0,0 +1,74 @@
+package quartz_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/coder/quartz"
+)
+
+func TestTypedTrap(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mClock := quartz.NewMock(t)
+	timerTrap := quartz.TrapNewTimer(mClock.Trap(), "typed")
+	defer timerTrap.Close()
+	sinceTrap := quartz.TrapSince(mClock.Trap(), "typed")
+	defer sinceTrap.Close()
+
+	start := mClock.Now()
+	go mClock.NewTimer(time.Minute, "typed")
+	tc := timerTrap.MustWait(ctx)
+	tc.MustRelease(ctx)
+	if tc.Duration != time.Minute {
+		t.Fatalf("expected duration 1m, got %s", tc.Duration)
+	}
+
+	go mClock.Since(start, "typed")
+	sc := sinceTrap.MustWait(ctx)
+	sc.MustRelease(ctx)
+	if !sc.Time.Equal(start) {
+		t.Fatalf("expected time %s, got %s", start, sc.Time)
+	}
+}
+
+func TestTypedTrap_Harness(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mClock := quartz.NewMock(t)
+	start := mClock.Now()
+	advanceTrap := quartz.TrapAdvance(mClock.Trap())
+	defer advanceTrap.Close()
+	nextTrap := quartz.TrapAdvanceNext(mClock.Trap())
+	defer nextTrap.Close()
+	setTrap := quartz.TrapSet(mClock.Trap())
+	defer setTrap.Close()
+
+	mClock.AfterFunc(time.Minute, func() {})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		mClock.Advance(time.Second).MustWait(ctx)
+		_, w := mClock.AdvanceNext()
+		w.MustWait(ctx)
+		mClock.Set(start.Add(time.Hour)).MustWait(ctx)
+	}()
+
+	ac := advanceTrap.MustWait(ctx)
+	if ac.Duration != time.Second {
+		t.Fatalf("expected duration 1s, got %s", ac.Duration)
+	}
+	ac.MustRelease(ctx)
+	nextTrap.MustWait(ctx).MustRelease(ctx)
+	sc := setTrap.MustWait(ctx)
+	if !sc.Time.Equal(start.Add(time.Hour)) {
+		t.Fatalf("expected time %s, got %s", start.Add(time.Hour), sc.Time)
+	}
+	sc.MustRelease(ctx)
+	<-done
+}
@@ -0,0 +1,56 @@
+package quartz
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrWaitTimeout is returned by WaitFor if the condition is not met before the timeout.
+var ErrWaitTimeout = errors.New("timed out waiting for condition")
+
+// errConditionMet stops the ticker of WaitFor once the condition is met.
+var errConditionMet = errors.New("condition met")
+
+// WaitFor polls cond every interval on the given Clock until it returns true or an error, the
+// timeout elapses, or ctx expires. cond is first called immediately. It returns nil if cond was
+// met, the error returned by cond, ErrWaitTimeout, or the context error.
+//
+// Because the polling uses TickerFunc and AfterFunc on clk, WaitFor can be used in production code
+// and tested instantly with a Mock, by advancing it. The tags are passed to each Clock call.
+func WaitFor(
+	ctx context.Context, clk Clock, interval, timeout time.Duration, cond func() (bool, error), tags ...string,
+) error {
+	if ok, err := cond(); err != nil || ok {
+		return err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	timedOut := make(chan struct{})
+	tmr := clk.AfterFunc(timeout, func() {
+		close(timedOut)
+		cancel()
+	}, tags...)
+	defer tmr.Stop(tags...)
+	err := clk.TickerFunc(ctx, interval, func() error {
+		ok, err := cond()
+		if err != nil {
+			return err
+		}
+		if ok {
+			return errConditionMet
+		}
+		return nil
+	}, tags...).Wait(tags...)
+	switch {
+	case errors.Is(err, errConditionMet):
+		return nil
+	case err != nil && ctx.Err() != nil:
+		select {
+		case <-timedOut:
+			return ErrWaitTimeout
+		default:
+		}
+	}
+	return err
+}
@@ -0,0 +1,137 @@
+package quartz_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/coder/quartz"
+)
+
+func TestWaitFor(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mClock := quartz.NewMock(t)
+	trap := mClock.Trap().TickerFunc("waitfor")
+	defer trap.Close()
+
+	var polls atomic.Int64
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- quartz.WaitFor(ctx, mClock, time.Second, time.Minute, func() (bool, error) {
+			return polls.Add(1) == 3, nil
+		}, "waitfor")
+	}()
+	trap.MustWait(ctx).MustRelease(ctx)
+	mClock.Advance(time.Second).MustWait(ctx)
+	mClock.Advance(time.Second).MustWait(ctx)
+	if err := <-errCh; err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if n := polls.Load(); n != 3 {
+		t.Fatalf("expected 3 polls, got %d", n)
+	}
+}
+
+func TestWaitFor_Timeout(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mClock := quartz.NewMock(t)
+	trap := mClock.Trap().TickerFunc("waitfor")
+	defer trap.Close()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- quartz.WaitFor(ctx, mClock, time.Second, 3*time.Second, func() (bool, error) {
+			return false, nil
+		}, "waitfor")
+	}()
+	trap.MustWait(ctx).MustRelease(ctx)
+	for i := 0; i < 3; i++ {
+		mClock.Advance(time.Second).MustWait(ctx)
+	}
+	if err := <-errCh; !errors.Is(err, quartz.ErrWaitTimeout) {
+		t.Fatalf("expected ErrWaitTimeout, got %v", err)
+	}
+}
+
+// chanSemaphore is a Semaphore of weight 1 backed by a channel.
+type chanSemaphore chan struct{}
+
+func (s chanSemaphore) Acquire(ctx context.Context, _ int64) error {
+	select {
+	case s <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func TestAcquireWithin(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mClock := quartz.NewMock(t)
+	sem := make(chanSemaphore, 1)
+	if err := quartz.AcquireWithin(ctx, mClock, sem, time.Second); err != nil {
+		t.Fatalf("expected to acquire the free semaphore, got %v", err)
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- quartz.AcquireWithin(ctx, mClock, sem, time.Second, "acquire")
+	}()
+	if err := mClock.WaitForEvents(ctx, 1, "acquire"); err != nil {
+		t.Fatal(err)
+	}
+	mClock.Advance(time.Second).MustWait(ctx)
+	if err := <-errCh; !errors.Is(err, quartz.ErrAcquireTimeout) {
+		t.Fatalf("expected ErrAcquireTimeout, got %v", err)
+	}
+}
+
+func TestTryLockWithin(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mClock := quartz.NewMock(t)
+	trap := mClock.Trap().TickerFunc("lock")
+	defer trap.Close()
+
+	var mu sync.Mutex
+	mu.Lock()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- quartz.TryLockWithin(ctx, mClock, &mu, time.Second, time.Minute, "lock")
+	}()
+	trap.MustWait(ctx).MustRelease(ctx)
+	mClock.Advance(time.Second).MustWait(ctx)
+	mu.Unlock()
+	mClock.Advance(time.Second).MustWait(ctx)
+	if err := <-errCh; err != nil {
+		t.Fatalf("expected to acquire the lock, got %v", err)
+	}
+	if mu.TryLock() {
+		t.Fatal("expected the lock to be held")
+	}
+
+	go func() {
+		errCh <- quartz.TryLockWithin(ctx, mClock, &mu, time.Second, 3*time.Second, "lock")
+	}()
+	trap.MustWait(ctx).MustRelease(ctx)
+	for i := 0; i < 3; i++ {
+		mClock.Advance(time.Second).MustWait(ctx)
+	}
+	if err := <-errCh; !errors.Is(err, quartz.ErrAcquireTimeout) {
+		t.Fatalf("expected ErrAcquireTimeout, got %v", err)
+	}
+}
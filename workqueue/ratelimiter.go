@@ -0,0 +1,61 @@
+package workqueue
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimiter decides how long an item should wait before being retried.
+type RateLimiter[T comparable] interface {
+	// When returns how long to wait before adding item, and records the failure.
+	When(item T) time.Duration
+	// Forget stops tracking item.
+	Forget(item T)
+	// NumRequeues returns the number of failures recorded for item.
+	NumRequeues(item T) int
+}
+
+// ExponentialRateLimiter delays each item by base*2^failures, up to maxDelay.
+type ExponentialRateLimiter[T comparable] struct {
+	base     time.Duration
+	maxDelay time.Duration
+
+	mu       sync.Mutex
+	failures map[T]int
+}
+
+// NewExponentialRateLimiter returns a RateLimiter that backs off exponentially from base to maxDelay.
+func NewExponentialRateLimiter[T comparable](base, maxDelay time.Duration) *ExponentialRateLimiter[T] {
+	return &ExponentialRateLimiter[T]{
+		base:     base,
+		maxDelay: maxDelay,
+		failures: make(map[T]int),
+	}
+}
+
+func (r *ExponentialRateLimiter[T]) When(item T) time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	exp := r.failures[item]
+	r.failures[item]++
+	backoff := float64(r.base) * math.Pow(2, float64(exp))
+	if backoff > float64(r.maxDelay) {
+		return r.maxDelay
+	}
+	return time.Duration(backoff)
+}
+
+func (r *ExponentialRateLimiter[T]) Forget(item T) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.failures, item)
+}
+
+func (r *ExponentialRateLimiter[T]) NumRequeues(item T) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.failures[item]
+}
+
+var _ RateLimiter[string] = &ExponentialRateLimiter[string]{}
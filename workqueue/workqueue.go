@@ -0,0 +1,196 @@
+// Package workqueue provides a rate-limited, delayable work queue in the style of the Kubernetes
+// client-go workqueue, whose delays and rate limiting run on a quartz.Clock. Controllers built on
+// it can have their reconcile backoff tested instantly with a mock clock.
+package workqueue
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/coder/quartz"
+)
+
+// Queue is a work queue with the semantics of the client-go rate-limiting workqueue:
+//
+//   - An item is processed by at most one worker at a time; adding an item that is being processed
+//     causes it to be re-queued once Done is called.
+//   - Adding an item that is already queued has no effect.
+//   - AddAfter and AddRateLimited add the item once a delay has elapsed on the Clock. An item
+//     waiting for a delay waits only once, until the earliest time it was added for.
+type Queue[T comparable] struct {
+	clock   quartz.Clock
+	tags    []string
+	limiter RateLimiter[T]
+	delayed *quartz.DelayQueue[delayedItem[T]]
+	cancel  context.CancelFunc
+	stopped chan struct{}
+
+	mu         sync.Mutex
+	cond       *sync.Cond
+	queue      []T
+	dirty      map[T]struct{}
+	processing map[T]struct{}
+	// waiting holds the time each item waiting for a delay becomes ready. Items added again with a
+	// later time are not pushed to delayed, and the entries of those added with an earlier time are
+	// ignored once popped.
+	waiting      map[T]time.Time
+	shuttingDown bool
+}
+
+// delayedItem is an item waiting for a delay, with the time it becomes ready.
+type delayedItem[T comparable] struct {
+	item    T
+	readyAt time.Time
+}
+
+// New returns a Queue that measures delays on clk and rate limits with limiter. Delays are waited
+// on using a quartz.DelayQueue with the given tags, so tests can trap them. The queue must be shut
+// down with ShutDown to release its resources.
+func New[T comparable](clk quartz.Clock, limiter RateLimiter[T], tags ...string) *Queue[T] {
+	ctx, cancel := context.WithCancel(context.Background())
+	q := &Queue[T]{
+		clock:      clk,
+		tags:       tags,
+		limiter:    limiter,
+		delayed:    quartz.NewDelayQueue[delayedItem[T]](clk, tags...),
+		cancel:     cancel,
+		stopped:    make(chan struct{}),
+		dirty:      make(map[T]struct{}),
+		processing: make(map[T]struct{}),
+		waiting:    make(map[T]time.Time),
+	}
+	q.cond = sync.NewCond(&q.mu)
+	go q.waitingLoop(ctx)
+	return q
+}
+
+func (q *Queue[T]) waitingLoop(ctx context.Context) {
+	defer close(q.stopped)
+	for {
+		d, err := q.delayed.Pop(ctx)
+		if err != nil {
+			return
+		}
+		q.mu.Lock()
+		readyAt, ok := q.waiting[d.item]
+		current := ok && readyAt.Equal(d.readyAt)
+		if current {
+			delete(q.waiting, d.item)
+		}
+		q.mu.Unlock()
+		if current {
+			q.Add(d.item)
+		}
+	}
+}
+
+// Add marks item as needing processing.
+func (q *Queue[T]) Add(item T) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.shuttingDown {
+		return
+	}
+	if _, ok := q.dirty[item]; ok {
+		return
+	}
+	q.dirty[item] = struct{}{}
+	if _, ok := q.processing[item]; ok {
+		return
+	}
+	q.queue = append(q.queue, item)
+	q.cond.Signal()
+}
+
+// AddAfter adds item once the duration d has elapsed on the Clock.
+func (q *Queue[T]) AddAfter(item T, d time.Duration) {
+	if q.ShuttingDown() {
+		return
+	}
+	if d <= 0 {
+		q.Add(item)
+		return
+	}
+	readyAt := q.clock.Now(q.tags...).Add(d)
+	q.mu.Lock()
+	if at, ok := q.waiting[item]; ok && !readyAt.Before(at) {
+		q.mu.Unlock()
+		return
+	}
+	q.waiting[item] = readyAt
+	q.mu.Unlock()
+	q.delayed.Push(delayedItem[T]{item: item, readyAt: readyAt}, readyAt)
+}
+
+// AddRateLimited adds item after the delay given by the RateLimiter.
+func (q *Queue[T]) AddRateLimited(item T) {
+	q.AddAfter(item, q.limiter.When(item))
+}
+
+// Forget tells the RateLimiter to stop tracking item, e.g. because it was processed successfully.
+func (q *Queue[T]) Forget(item T) {
+	q.limiter.Forget(item)
+}
+
+// NumRequeues returns the number of times item has been rate limited since it was last forgotten.
+func (q *Queue[T]) NumRequeues(item T) int {
+	return q.limiter.NumRequeues(item)
+}
+
+// Get blocks until an item can be processed, and returns it. If the queue is shut down, shutdown
+// is true. Callers must call Done with the item once they finish processing it.
+func (q *Queue[T]) Get() (item T, shutdown bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for len(q.queue) == 0 && !q.shuttingDown {
+		q.cond.Wait()
+	}
+	if len(q.queue) == 0 {
+		return item, true
+	}
+	item = q.queue[0]
+	var zero T
+	q.queue[0] = zero
+	q.queue = q.queue[1:]
+	q.processing[item] = struct{}{}
+	delete(q.dirty, item)
+	return item, false
+}
+
+// Done marks item as done processing. If it was added again while being processed, it is
+// re-queued.
+func (q *Queue[T]) Done(item T) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	delete(q.processing, item)
+	if _, ok := q.dirty[item]; ok {
+		q.queue = append(q.queue, item)
+		q.cond.Signal()
+	}
+}
+
+// Len returns the number of items ready to be processed.
+func (q *Queue[T]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.queue)
+}
+
+// ShutDown causes Get to return shutdown once the queue is drained, and stops waiting for delayed
+// items.
+func (q *Queue[T]) ShutDown() {
+	q.mu.Lock()
+	q.shuttingDown = true
+	q.cond.Broadcast()
+	q.mu.Unlock()
+	q.cancel()
+	<-q.stopped
+}
+
+// ShuttingDown returns true if ShutDown has been called.
+func (q *Queue[T]) ShuttingDown() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.shuttingDown
+}
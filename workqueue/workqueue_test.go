@@ -0,0 +1,112 @@
+package workqueue_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/coder/quartz"
+	"github.com/coder/quartz/workqueue"
+)
+
+func TestQueue_RateLimitedBackoff(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mClock := quartz.NewMock(t)
+	trap := mClock.Trap().NewTimer("workqueue")
+	defer trap.Close()
+
+	q := workqueue.New[string](mClock,
+		workqueue.NewExponentialRateLimiter[string](time.Second, 5*time.Second), "workqueue")
+	defer q.ShutDown()
+
+	q.Add("item")
+	for _, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second} {
+		item, shutdown := q.Get()
+		if shutdown || item != "item" {
+			t.Fatalf("unexpected Get: %q %v", item, shutdown)
+		}
+		// reconcile failed, requeue with backoff
+		q.AddRateLimited(item)
+		q.Done(item)
+
+		c := trap.MustWait(ctx)
+		c.MustRelease(ctx)
+		if c.Duration != want {
+			t.Fatalf("expected backoff %s got %s", want, c.Duration)
+		}
+		mClock.Advance(want).MustWait(ctx)
+	}
+	item, _ := q.Get()
+	q.Forget(item)
+	q.Done(item)
+	if n := q.NumRequeues("item"); n != 0 {
+		t.Fatalf("expected 0 requeues, got %d", n)
+	}
+}
+
+func TestQueue_Dedupe(t *testing.T) {
+	t.Parallel()
+
+	q := workqueue.New[string](quartz.NewMock(t), workqueue.NewExponentialRateLimiter[string](time.Second, time.Minute))
+	q.Add("a")
+	q.Add("a")
+	if q.Len() != 1 {
+		t.Fatalf("expected 1 item, got %d", q.Len())
+	}
+	item, _ := q.Get()
+	// adding while processing requeues after Done
+	q.Add(item)
+	if q.Len() != 0 {
+		t.Fatalf("expected 0 items, got %d", q.Len())
+	}
+	q.Done(item)
+	if q.Len() != 1 {
+		t.Fatalf("expected 1 item, got %d", q.Len())
+	}
+	q.ShutDown()
+	if _, shutdown := q.Get(); shutdown {
+		t.Fatal("expected to drain queue before shutdown")
+	}
+	if _, shutdown := q.Get(); !shutdown {
+		t.Fatal("expected shutdown")
+	}
+}
+
+func TestQueue_AddAfterEarliest(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mClock := quartz.NewMock(t)
+	start := mClock.Now()
+	trap := mClock.Trap().NewTimer("workqueue")
+	defer trap.Close()
+	q := workqueue.New[string](mClock,
+		workqueue.NewExponentialRateLimiter[string](time.Second, time.Minute), "workqueue")
+	defer q.ShutDown()
+
+	q.AddAfter("a", time.Minute)
+	trap.MustWait(ctx).MustRelease(ctx)
+	// adding the item again for an earlier time replaces the later one.
+	q.AddAfter("a", time.Second)
+	c := trap.MustWait(ctx)
+	c.MustRelease(ctx)
+	if c.Duration != time.Second {
+		t.Fatalf("expected to wait 1s, got %s", c.Duration)
+	}
+	mClock.Advance(time.Second).MustWait(ctx)
+	item, _ := q.Get()
+	q.Done(item)
+
+	// the later time is ignored once reached.
+	trap.MustWait(ctx).MustRelease(ctx)
+	mClock.AdvanceTo(start.Add(time.Minute)).MustWait(ctx)
+	q.AddAfter("b", time.Second)
+	trap.MustWait(ctx).MustRelease(ctx)
+	if n := q.Len(); n != 0 {
+		t.Fatalf("expected no items ready, got %d", n)
+	}
+}